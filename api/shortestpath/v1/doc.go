@@ -12,28 +12,9 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package crawling
+// Package shortestpathv1 contains the messages and the service generated from shortest_path.proto.
+// Run go generate or make generate after changing the proto file, the code is generated by buf
+// with protoc-gen-go and protoc-gen-go-grpc.
+package shortestpathv1
 
-type TraversalState struct {
-	PageURI     string
-	Predecessor *TraversalState
-	Ancestors   []*TraversalState
-}
-
-type TraversalResult struct {
-	successState *TraversalState
-}
-
-func (tr TraversalResult) foundPath() bool {
-	return tr.successState != nil
-}
-
-func (tr TraversalResult) VisitedPages() (visitedPages []string) {
-	currentState := tr.successState
-
-	for currentState != nil {
-		visitedPages = append(visitedPages, currentState.PageURI)
-		currentState = currentState.Predecessor
-	}
-	return
-}
+//go:generate buf generate --template ../../buf.gen.yaml --output ../.. ../..
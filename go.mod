@@ -3,8 +3,18 @@ module github.com/baez90/shortest-path
 go 1.13
 
 require (
+	github.com/golang/protobuf v1.4.3
+	github.com/prometheus/client_golang v1.2.1
+	github.com/prometheus/client_model v0.0.0-20190812154241-14fe0d1b01d4
 	github.com/sirupsen/logrus v1.4.2
 	github.com/spf13/cobra v0.0.5
+	github.com/spf13/pflag v1.0.3
 	github.com/spf13/viper v1.4.0
-	golang.org/x/net v0.0.0-20190522155817-f3200d17e092
+	go.etcd.io/bbolt v1.3.5
+	go.opentelemetry.io/otel v0.6.0
+	go.opentelemetry.io/otel/exporters/otlp v0.6.0
+	golang.org/x/net v0.0.0-20191002035440-2ec189313ef0
+	google.golang.org/grpc v1.34.0
+	google.golang.org/protobuf v1.25.0
+	gopkg.in/yaml.v2 v2.2.7
 )
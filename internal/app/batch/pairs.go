@@ -0,0 +1,219 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package batch searches the shortest paths of many pairs of pages sharing the links of all processed pages.
+package batch
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// InputFormat is the encoding of the pairs to search
+type InputFormat string
+
+const (
+	// InputFormatAuto detects JSON lines by an opening brace and reads CSV otherwise
+	InputFormatAuto  InputFormat = "auto"
+	InputFormatCSV   InputFormat = "csv"
+	InputFormatJSONL InputFormat = "jsonl"
+
+	// maxLineLength limits the length of a single JSON line
+	maxLineLength = 1 << 20
+)
+
+var (
+	inputFormats = []InputFormat{InputFormatAuto, InputFormatCSV, InputFormatJSONL}
+	// ErrIncompletePair is the error of pairs without start or target
+	ErrIncompletePair = errors.New("pair requires start and target")
+)
+
+func ParseInputFormat(value string) (format InputFormat, err error) {
+	for _, format = range inputFormats {
+		if strings.EqualFold(string(format), value) {
+			return
+		}
+	}
+	err = fmt.Errorf("unknown input format %s, supported formats are %v", value, inputFormats)
+	return
+}
+
+// Pair is a single search of a batch, start and target are article URLs or titles
+type Pair struct {
+	// Number is the position of the pair in the input beginning with 1
+	Number int
+	Start  string
+	Target string
+	// Err is set if the pair could not be read e.g. because it is malformed
+	Err error
+}
+
+// PairReader reads pairs one by one.
+// CSV records have the columns start and target, a header row naming the columns start and target is optional.
+// JSON lines are objects with the fields start and target. Blank lines are skipped.
+type PairReader struct {
+	csv   *csv.Reader
+	lines *bufio.Scanner
+	// columns are the indexes of the columns start and target, headerRead is set after the first CSV record
+	columns    map[string]int
+	headerRead bool
+	number     int
+}
+
+type jsonPair struct {
+	Start  string `json:"start"`
+	Target string `json:"target"`
+}
+
+// NewPairReader creates a reader of the pairs in r, the automatic format detection peeks at the beginning of r
+func NewPairReader(r io.Reader, format InputFormat) (reader *PairReader, err error) {
+	buffered := bufio.NewReader(r)
+	if format == InputFormatAuto {
+		if format, err = detectFormat(buffered); err != nil {
+			return
+		}
+	}
+
+	reader = &PairReader{columns: map[string]int{"start": 0, "target": 1}}
+	switch format {
+	case InputFormatCSV:
+		reader.csv = csv.NewReader(buffered)
+		reader.csv.FieldsPerRecord = -1
+		reader.csv.TrimLeadingSpace = true
+		reader.csv.ReuseRecord = true
+	case InputFormatJSONL:
+		reader.lines = bufio.NewScanner(buffered)
+		reader.lines.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+	default:
+		return nil, fmt.Errorf("unknown input format %s", format)
+	}
+	return
+}
+
+// detectFormat skips leading white space and peeks at the first character
+func detectFormat(r *bufio.Reader) (format InputFormat, err error) {
+	for {
+		var next []byte
+		if next, err = r.Peek(1); err == io.EOF {
+			return InputFormatCSV, nil
+		} else if err != nil {
+			return
+		}
+		switch next[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = r.ReadByte()
+		case '{':
+			return InputFormatJSONL, nil
+		default:
+			return InputFormatCSV, nil
+		}
+	}
+}
+
+// Next returns the next pair, malformed pairs are returned with their error.
+// At the end of the input io.EOF is returned, other errors are failures reading the input.
+func (reader *PairReader) Next() (pair Pair, err error) {
+	if reader.csv != nil {
+		pair, err = reader.nextCSV()
+	} else {
+		pair, err = reader.nextJSON()
+	}
+	if err != nil {
+		return
+	}
+
+	reader.number++
+	pair.Number = reader.number
+	if pair.Err == nil && (pair.Start == "" || pair.Target == "") {
+		pair.Err = ErrIncompletePair
+	}
+	return
+}
+
+func (reader *PairReader) nextCSV() (pair Pair, err error) {
+	var record []string
+	for {
+		record, err = reader.csv.Read()
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			reader.headerRead = true
+			pair.Err = err
+			return pair, nil
+		} else if err != nil {
+			return
+		}
+
+		// the header is only expected in the first record
+		if !reader.headerRead {
+			reader.headerRead = true
+			if reader.readHeader(record) {
+				continue
+			}
+		}
+		break
+	}
+
+	pair.Start = field(record, reader.columns["start"])
+	pair.Target = field(record, reader.columns["target"])
+	return
+}
+
+// readHeader takes the columns from record if it names the columns start and target
+func (reader *PairReader) readHeader(record []string) bool {
+	columns := make(map[string]int)
+	for idx, name := range record {
+		columns[strings.ToLower(strings.TrimSpace(name))] = idx
+	}
+	_, hasStart := columns["start"]
+	_, hasTarget := columns["target"]
+	if hasStart && hasTarget {
+		reader.columns = columns
+	}
+	return hasStart && hasTarget
+}
+
+func field(record []string, idx int) string {
+	if idx >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[idx])
+}
+
+func (reader *PairReader) nextJSON() (pair Pair, err error) {
+	for reader.lines.Scan() {
+		line := strings.TrimSpace(reader.lines.Text())
+		if line == "" {
+			continue
+		}
+
+		var decoded jsonPair
+		if decodeErr := json.Unmarshal([]byte(line), &decoded); decodeErr != nil {
+			pair.Err = fmt.Errorf("malformed pair: %w", decodeErr)
+			return
+		}
+		pair.Start = strings.TrimSpace(decoded.Start)
+		pair.Target = strings.TrimSpace(decoded.Target)
+		return
+	}
+
+	if err = reader.lines.Err(); err == nil {
+		err = io.EOF
+	}
+	return
+}
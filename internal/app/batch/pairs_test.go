@@ -0,0 +1,116 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPairReader_Next(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		format    InputFormat
+		wantPairs []Pair
+		wantErrs  []bool
+	}{
+		{
+			name:   "CSV without header",
+			input:  "Alpha,Delta\n\nhttps://en.wikipedia.org/wiki/Beta, Gamma\n",
+			format: InputFormatAuto,
+			wantPairs: []Pair{
+				{Number: 1, Start: "Alpha", Target: "Delta"},
+				{Number: 2, Start: "https://en.wikipedia.org/wiki/Beta", Target: "Gamma"},
+			},
+			wantErrs: []bool{false, false},
+		},
+		{
+			name:   "CSV with header in any column order",
+			input:  "id,target,start\n1,Delta,Alpha\n2,Gamma\n",
+			format: InputFormatCSV,
+			wantPairs: []Pair{
+				{Number: 1, Start: "Alpha", Target: "Delta"},
+				{Number: 2, Target: "Gamma"},
+			},
+			wantErrs: []bool{false, true},
+		},
+		{
+			name:   "malformed CSV record",
+			input:  "Alpha,Delta\n\"Beta,Gamma\nDelta,\"Alpha\"\"\n",
+			format: InputFormatCSV,
+			wantPairs: []Pair{
+				{Number: 1, Start: "Alpha", Target: "Delta"},
+				{Number: 2},
+			},
+			wantErrs: []bool{false, true},
+		},
+		{
+			name:   "JSON lines",
+			input:  "  \n{\"start\": \"Alpha\", \"target\": \"Delta\"}\n\n{\"start\": \"Beta\"\n{\"start\": \"Gamma\", \"target\": \"Alpha\", \"id\": 3}\n",
+			format: InputFormatAuto,
+			wantPairs: []Pair{
+				{Number: 1, Start: "Alpha", Target: "Delta"},
+				{Number: 2},
+				{Number: 3, Start: "Gamma", Target: "Alpha"},
+			},
+			wantErrs: []bool{false, true, false},
+		},
+		{
+			name:   "empty input",
+			input:  "",
+			format: InputFormatAuto,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewPairReader(strings.NewReader(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("NewPairReader() error = %v", err)
+			}
+
+			var gotPairs []Pair
+			var gotErrs []bool
+			for {
+				pair, err := reader.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Next() error = %v", err)
+				}
+				gotErrs = append(gotErrs, pair.Err != nil)
+				pair.Err = nil
+				gotPairs = append(gotPairs, pair)
+			}
+			if !reflect.DeepEqual(gotPairs, tt.wantPairs) {
+				t.Errorf("Next() pairs = %+v, want %+v", gotPairs, tt.wantPairs)
+			}
+			if !reflect.DeepEqual(gotErrs, tt.wantErrs) {
+				t.Errorf("Next() errors = %v, want %v", gotErrs, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestPairReader_Next_incomplete(t *testing.T) {
+	reader, _ := NewPairReader(strings.NewReader(`{"start": "Alpha"}`), InputFormatJSONL)
+	if pair, err := reader.Next(); err != nil || !errors.Is(pair.Err, ErrIncompletePair) {
+		t.Errorf("Next() = %+v, %v, want ErrIncompletePair", pair, err)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"github.com/baez90/shortest-path/internal/app/output"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	"io"
+	"sync"
+	"time"
+)
+
+// Config are the settings applying to all pairs of a batch
+type Config struct {
+	// Resolve resolves start and target of a pair to the pages to search between
+	Resolve func(start, target string) (startRef, targetRef crawling.PageReference, err error)
+	// Options configure every search in addition to the options of the engine
+	Options []crawling.Option
+	// Parallelism is the number of searches running at the same time, at least 1
+	Parallelism int
+	// SearchTimeout limits the duration of every search, 0 disables the limit
+	SearchTimeout time.Duration
+}
+
+// Result is the outcome of a single pair
+type Result struct {
+	output.BatchResult
+	// Err is the error of the pair, ErrMaxHopsReached if the target was not found
+	Err error
+}
+
+// Run searches the pairs of reader with the searches of engine and passes their results to write in the order of the pairs.
+// When ctx is cancelled no further pairs are started, the running searches are cancelled and their results passed to write.
+// The returned error is a failure reading the pairs or the first error of write, which stops the batch as well.
+func Run(ctx context.Context, engine *crawling.Engine, reader *PairReader, config Config, write func(Result) error) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pairs := make(chan Pair)
+	results := make(chan Result)
+
+	var readErr error
+	go func() {
+		defer close(pairs)
+		for {
+			pair, nextErr := reader.Next()
+			if nextErr != nil {
+				if nextErr != io.EOF {
+					readErr = nextErr
+				}
+				return
+			}
+			select {
+			case pairs <- pair:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	workers := sync.WaitGroup{}
+	for i := 0; i < config.Parallelism; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for pair := range pairs {
+				results <- config.search(ctx, engine, pair)
+			}
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	// results arrive in the order the searches finish, pending keeps them until all previous pairs are written
+	pending := make(map[int]Result)
+	next := 1
+	for result := range results {
+		pending[result.Pair] = result
+		for result, ok := pending[next]; ok; result, ok = pending[next] {
+			delete(pending, next)
+			next++
+			if err == nil {
+				if err = write(result); err != nil {
+					cancel()
+				}
+			}
+		}
+	}
+
+	// the reader is done as soon as pairs is closed which happened before all workers finished
+	if err == nil {
+		err = readErr
+	}
+	return
+}
+
+// search runs the search of a single pair, pairs which cannot be resolved fail without search
+func (config Config) search(ctx context.Context, engine *crawling.Engine, pair Pair) (result Result) {
+	started := time.Now()
+	result.Pair = pair.Number
+	result.SearchResult = output.SearchResult{
+		Start:  pair.Start,
+		Target: pair.Target,
+		Path:   make([]output.Page, 0),
+	}
+
+	var start, target crawling.PageReference
+	if result.Err = pair.Err; result.Err == nil {
+		start, target, result.Err = config.Resolve(pair.Start, pair.Target)
+	}
+	if result.Err != nil {
+		result.Error = result.Err.Error()
+		result.DurationMillis = time.Since(started).Milliseconds()
+		return
+	}
+
+	if config.SearchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.SearchTimeout)
+		defer cancel()
+	}
+
+	crawler := engine.NewSearch(start, target, config.Options...)
+	var res crawling.TraversalResult
+	res, result.Err = crawler.SearchShortestPath(ctx)
+	result.SearchResult = output.NewSearchResult(crawler, res, result.Err, time.Since(started))
+	return
+}
@@ -0,0 +1,134 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"errors"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	"github.com/baez90/shortest-path/pkg/crawling/crawlingtest"
+	"strings"
+	"testing"
+)
+
+func resolveTestPages(start, target string) (startRef, targetRef crawling.PageReference, err error) {
+	return crawling.ResolvePages(start, target, crawlingtest.WikiBase, crawling.BuiltinSiteProfiles())
+}
+
+func TestRun(t *testing.T) {
+	type want struct {
+		found bool
+		hops  int
+		err   error
+	}
+	tests := []struct {
+		name           string
+		input          string
+		parallelism    int
+		want           []want
+		wantMaxFetches int
+	}{
+		{
+			name:        "share links between pairs",
+			input:       "Page 0,Page 4\nPage 1,Page 4\nPage 2,Page 5\n",
+			parallelism: 1,
+			want: []want{
+				{found: true, hops: 4},
+				{found: true, hops: 3},
+				{found: true, hops: 3},
+			},
+			wantMaxFetches: 1,
+		},
+		{
+			name:        "keep order of pairs and report failures",
+			input:       "Page 0,Page 8\nPage 0,Page 1\nPage 0\nhttps://en.wikipedia.org/wiki/Page_0,https://de.wikipedia.org/wiki/Page_1\nPage 5,Page 0\nPage 3,Page 4\n",
+			parallelism: 4,
+			want: []want{
+				{found: true, hops: 8},
+				{found: true, hops: 1},
+				{err: ErrIncompletePair},
+				{err: crawling.ErrDifferentWikis},
+				{err: crawling.ErrMaxHopsReached},
+				{found: true, hops: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wiki := crawlingtest.NewWiki(crawlingtest.Chain)
+			engine := crawling.NewEngine(1<<20, crawling.WithPageCache(crawling.NewPageCache(wiki.Fetch, 0)))
+			reader, _ := NewPairReader(strings.NewReader(tt.input), InputFormatCSV)
+
+			var got []Result
+			err := Run(context.Background(), engine, reader, Config{
+				Resolve:     resolveTestPages,
+				Options:     []crawling.Option{crawling.WithMaxHops(10)},
+				Parallelism: tt.parallelism,
+			}, func(result Result) error {
+				got = append(got, result)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Run() wrote %d results, want %d", len(got), len(tt.want))
+			}
+			for idx, result := range got {
+				if result.Pair != idx+1 {
+					t.Errorf("result %d belongs to pair %d", idx+1, result.Pair)
+				}
+				want := tt.want[idx]
+				if result.Found != want.found || result.Hops != want.hops || !errors.Is(result.Err, want.err) {
+					t.Errorf("pair %d found = %v, hops = %d, error = %v, want found = %v, hops = %d, error = %v", result.Pair, result.Found, result.Hops, result.Err, want.found, want.hops, want.err)
+				}
+				if (result.Err != nil) != (result.Error != "") {
+					t.Errorf("pair %d error = %q, want the message of %v", result.Pair, result.Error, result.Err)
+				}
+			}
+
+			if tt.wantMaxFetches > 0 {
+				for title, fetches := range wiki.Fetches() {
+					if fetches > tt.wantMaxFetches {
+						t.Errorf("retrieved %s %d times, want at most %d", title, fetches, tt.wantMaxFetches)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRun_writeError(t *testing.T) {
+	wiki := crawlingtest.NewWiki(crawlingtest.Chain)
+	engine := crawling.NewEngine(1<<20, crawling.WithPageCache(crawling.NewPageCache(wiki.Fetch, 0)))
+	reader, _ := NewPairReader(strings.NewReader(strings.Repeat("Page 0,Page 3\n", 50)), InputFormatCSV)
+
+	writeErr := errors.New("disk full")
+	writes := 0
+	err := Run(context.Background(), engine, reader, Config{
+		Resolve:     resolveTestPages,
+		Parallelism: 2,
+	}, func(Result) error {
+		writes++
+		return writeErr
+	})
+	if err != writeErr {
+		t.Errorf("Run() error = %v, want %v", err, writeErr)
+	}
+	if writes != 1 {
+		t.Errorf("Run() wrote %d results after the first write failed", writes-1)
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"github.com/baez90/shortest-path/internal/app/batch"
+	"github.com/baez90/shortest-path/internal/app/output"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"os"
+	"time"
+)
+
+var (
+	batchCmd = &cobra.Command{
+		Use:     "batch [pairs-file]",
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: validateBatchInputs,
+		Short:   "Search the shortest paths of many pairs of articles",
+		Long: `Search the shortest path of every start and target pair of the given file or of stdin if the file is - or missing.
+
+Pairs are CSV records with the columns start and target, optionally preceded by a header row naming them,
+or JSON lines like {"start": "Times New Roman", "target": "Great Britain"}. The format is detected by the first
+character unless it is set by --input-format.
+
+Up to --parallelism searches run at the same time and share the links of all processed pages in memory limited
+by --link-cache-size. One result row per pair is written to stdout in the order of the input, including pairs
+which failed or did not reach their target.
+
+The exit code is 1 if any pair failed, otherwise 2 if the target of any pair was not found.`,
+		Run: runBatchCommand,
+	}
+)
+
+func init() {
+	addSearchFlags(batchCmd)
+	batchCmd.Flags().String("input-format", string(batch.InputFormatAuto), "format of the pairs: auto, csv or jsonl")
+	batchCmd.Flags().StringP("output", "o", string(output.BatchFormatCSV), "output format of the results: csv or jsonl")
+	batchCmd.Flags().Uint("parallelism", 4, "number of searches running at the same time, at least 1")
+	batchCmd.Flags().Uint64("link-cache-size", 256<<20, "bytes of links of processed pages to keep in memory and share between all searches, 0 disables sharing links")
+	batchCmd.Flags().Duration("search-timeout", 0, "maximum duration of a single search, 0 disables the limit")
+	rootCmd.AddCommand(batchCmd)
+}
+
+// validateBatchInputs validates the settings shared by the searches of all pairs
+func validateBatchInputs(cmd *cobra.Command, args []string) (err error) {
+	if err = validateSearchInputs(cmd, args); err != nil {
+		return
+	}
+	if _, err = batch.ParseInputFormat(viper.GetString("input-format")); err != nil {
+		return
+	}
+	if _, err = output.ParseBatchFormat(viper.GetString("output")); err != nil {
+		return
+	}
+	if _, err = batchParallelism(); err != nil {
+		return
+	}
+	if _, err = uintSetting("link-cache-size", 64); err != nil {
+		return
+	}
+	if viper.GetDuration("search-timeout") < 0 {
+		err = errors.New("search-timeout must not be negative")
+	}
+	return
+}
+
+func batchParallelism() (parallelism int, err error) {
+	var value uint64
+	if value, err = uintSetting("parallelism", 16); err == nil && value == 0 {
+		err = errors.New("parallelism has to be at least 1")
+	}
+	return int(value), err
+}
+
+func runBatchCommand(cmd *cobra.Command, args []string) {
+	// the settings were validated by validateBatchInputs already
+	inputFormat, _ := batch.ParseInputFormat(viper.GetString("input-format"))
+	outputFormat, _ := output.ParseBatchFormat(viper.GetString("output"))
+	parallelism, _ := batchParallelism()
+	linkCacheSize, _ := uintSetting("link-cache-size", 64)
+
+	input, err := openPairs(args)
+	if err != nil {
+		log.
+			WithError(err).
+			Error("Failed to open pairs")
+		os.Exit(exitCodeError)
+	}
+	defer input.Close()
+
+	reader, err := batch.NewPairReader(input, inputFormat)
+	if err != nil {
+		log.
+			WithError(err).
+			Error("Failed to read pairs")
+		os.Exit(exitCodeError)
+	}
+
+	options, err := searchOptions()
+	if err != nil {
+		log.
+			WithError(err).
+			Error("Failed to setup crawler")
+		os.Exit(exitCodeError)
+	}
+	resolve, err := pageResolver(cmd.Flags())
+	if err != nil {
+		log.
+			WithError(err).
+			Error("Failed to setup crawler")
+		os.Exit(exitCodeError)
+	}
+
+	engine := crawling.NewEngine(linkCacheSize, options...)
+	if searchMetrics != nil {
+		searchMetrics.RegisterLinkCache(engine.LinkCache())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cancelOnSignal(ctx, cancel)
+
+	writer := output.NewBatchWriter(os.Stdout, outputFormat)
+	summary := batchSummary{}
+	start := time.Now()
+	err = batch.Run(ctx, engine, reader, batch.Config{
+		Resolve:       resolve,
+		Parallelism:   parallelism,
+		SearchTimeout: viper.GetDuration("search-timeout"),
+	}, func(result batch.Result) error {
+		summary.add(result)
+		return writer.Write(result.BatchResult)
+	})
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	interrupted := ctx.Err() != nil
+	closeSearchTracing()
+
+	hits, misses := engine.LinkCache().Stats()
+	log.Infof("%d pairs in %d ms: %d found, %d not found, %d failed", summary.pairs, time.Since(start).Milliseconds(), summary.found, summary.notFound, summary.failed)
+	log.Infof("Link cache answered %d of %d processed pages", hits, hits+misses)
+	writeMetricsFile()
+
+	switch {
+	case err != nil:
+		log.
+			WithError(err).
+			Error("Failed to run batch")
+		os.Exit(exitCodeError)
+	case interrupted:
+		log.Warnf("Batch interrupted after %d pairs", summary.pairs)
+		os.Exit(exitCodeInterrupted)
+	}
+	if exitCode := summary.exitCode(); exitCode != 0 {
+		os.Exit(exitCode)
+	}
+}
+
+// openPairs opens the file of the pairs or stdin if it is - or missing
+func openPairs(args []string) (io.ReadCloser, error) {
+	if len(args) == 0 || args[0] == "-" {
+		return os.Stdin, nil
+	}
+	return os.Open(args[0])
+}
+
+// batchSummary counts the outcomes of the pairs of a batch
+type batchSummary struct {
+	pairs    int
+	found    int
+	notFound int
+	failed   int
+}
+
+func (summary *batchSummary) add(result batch.Result) {
+	summary.pairs++
+	switch searchExitCode(result.Err, false) {
+	case 0:
+		summary.found++
+		log.Infof("Pair %d: %d hops in %d ms", result.Pair, result.Hops, result.DurationMillis)
+	case exitCodeNotFound:
+		summary.notFound++
+		log.Infof("Pair %d: target not reached within max hops after %d ms", result.Pair, result.DurationMillis)
+	default:
+		summary.failed++
+		log.Infof("Pair %d: failed after %d ms: %v", result.Pair, result.DurationMillis, result.Err)
+	}
+}
+
+// exitCode lets a failed pair take precedence over a pair which did not reach the target
+func (summary batchSummary) exitCode() int {
+	switch {
+	case summary.failed > 0:
+		return exitCodeError
+	case summary.notFound > 0:
+		return exitCodeNotFound
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"os"
+	"time"
+)
+
+var (
+	benchCmd = &cobra.Command{
+		Use:     "bench <start> <target>",
+		Args:    cobra.ExactArgs(2),
+		PreRunE: validateBenchInputs,
+		Short:   "Measure the duration of repeated searches between two articles",
+		Long: `Search the shortest path between two articles several times with a fresh crawler each
+and report duration, fetched and discovered pages of every run as well as their minimum, average and maximum duration.`,
+		Run: runBenchCommand,
+	}
+)
+
+func init() {
+	addSearchFlags(benchCmd)
+	benchCmd.Flags().Uint("runs", 3, "number of searches to run, at least 1")
+	rootCmd.AddCommand(benchCmd)
+}
+
+// benchRun is the measurement of a single search
+type benchRun struct {
+	duration        time.Duration
+	hops            int
+	fetchedPages    uint
+	discoveredPages int
+	err             error
+}
+
+// validateBenchInputs validates the search and requires at least one run
+func validateBenchInputs(cmd *cobra.Command, args []string) (err error) {
+	if err = validateSearchInputs(cmd, args); err != nil {
+		return
+	}
+	_, err = benchRuns()
+	return
+}
+
+func benchRuns() (runs int, err error) {
+	var value uint64
+	if value, err = uintSetting("runs", 16); err == nil && value == 0 {
+		err = errors.New("runs has to be at least 1")
+	}
+	return int(value), err
+}
+
+func runBenchCommand(cmd *cobra.Command, args []string) {
+	// the number of runs was validated by validateBenchInputs already
+	runs, _ := benchRuns()
+
+	results := make([]benchRun, 0, runs)
+	for i := 0; i < runs; i++ {
+		crawler, err := setupCrawler(cmd.Flags(), args)
+		if err != nil {
+			log.
+				WithError(err).
+				Error("Failed to setup crawler")
+			os.Exit(setupExitCode(err))
+		}
+
+		res, duration, interrupted, err := runSearch(crawler)
+		if interrupted {
+			reportInterruptedSearch(crawler, duration)
+			os.Exit(exitCodeInterrupted)
+		}
+
+		run := benchRun{
+			duration:        duration,
+			hops:            res.Hops(),
+			fetchedPages:    crawler.FetchedPages(),
+			discoveredPages: crawler.DiscoveredPages(),
+			err:             err,
+		}
+		results = append(results, run)
+		fmt.Println(run.String(i + 1))
+	}
+
+	fmt.Println(benchSummary(results))
+
+	// a failed run takes precedence over a run which did not reach the target
+	exitCode := 0
+	for _, run := range results {
+		if code := searchExitCode(run.err, false); code == exitCodeError || exitCode == 0 {
+			exitCode = code
+		}
+	}
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+}
+
+func (run benchRun) String(number int) string {
+	if run.err != nil {
+		return fmt.Sprintf("Run %d: failed after %d ms: %v, fetched %d pages, discovered %d pages", number, run.duration.Milliseconds(), run.err, run.fetchedPages, run.discoveredPages)
+	}
+	return fmt.Sprintf("Run %d: %d hops in %d ms, fetched %d pages, discovered %d pages", number, run.hops, run.duration.Milliseconds(), run.fetchedPages, run.discoveredPages)
+}
+
+func benchSummary(results []benchRun) string {
+	min, max, total := results[0].duration, results[0].duration, time.Duration(0)
+	for _, run := range results {
+		total += run.duration
+		if run.duration < min {
+			min = run.duration
+		}
+		if run.duration > max {
+			max = run.duration
+		}
+	}
+	avg := total / time.Duration(len(results))
+	return fmt.Sprintf("%d runs: min %d ms, avg %d ms, max %d ms", len(results), min.Milliseconds(), avg.Milliseconds(), max.Milliseconds())
+}
@@ -0,0 +1,300 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	shortestpathv1 "github.com/baez90/shortest-path/api/shortestpath/v1"
+	"github.com/baez90/shortest-path/internal/app/output"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"os"
+)
+
+var (
+	clientCmd = &cobra.Command{
+		Use:   "client",
+		Short: "Query the gRPC API of a running serve command",
+		Long: `Send path and link queries to the gRPC API of a server started with serve --grpc-listen.
+
+The server resolves titles in its default wiki unless --wiki or --lang is set explicitly.
+Rejected queries exit with 64, interrupted queries with 130 and searches not reaching the target with 2.`,
+	}
+	clientPathCmd = &cobra.Command{
+		Use:     "path <start> <target>",
+		Args:    cobra.ExactArgs(2),
+		PreRunE: validateClientSearchInputs,
+		Short:   "Search the shortest path between two articles on the server",
+		Run:     runClientPathCommand,
+	}
+	clientWatchCmd = &cobra.Command{
+		Use:     "watch <start> <target>",
+		Args:    cobra.ExactArgs(2),
+		PreRunE: validateClientSearchInputs,
+		Short:   "Search the shortest path on the server and log the progress of the search",
+		Run:     runClientWatchCommand,
+	}
+	clientLinksCmd = &cobra.Command{
+		Use:     "links <page>",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: validateClientLinksInputs,
+		Short:   "Explain which links of a page the search on the server follows",
+		Run:     runClientLinksCommand,
+	}
+)
+
+func init() {
+	clientCmd.PersistentFlags().String("server", "localhost:9090", "address of the gRPC API to query")
+	clientCmd.PersistentFlags().Duration("timeout", 0, "maximum duration of the query, 0 disables the limit")
+
+	for _, cmd := range []*cobra.Command{clientPathCmd, clientWatchCmd} {
+		cmd.Flags().Uint16("max-hops", 0, "depth of the search, 0 applies the limit of the server")
+		cmd.Flags().Bool("interlanguage", false, "follow interlanguage links to other language editions, start and target may belong to different languages")
+		cmd.Flags().Bool("link-context", false, "capture anchor text, section and sentence of every link on the path")
+		cmd.Flags().StringP("output", "o", string(output.FormatText), "output format of the result: text, json, csv or yaml")
+		clientCmd.AddCommand(cmd)
+	}
+
+	clientLinksCmd.Flags().Bool("kept-only", false, "list only the links the search follows")
+	clientLinksCmd.Flags().Bool("interlanguage", false, "follow interlanguage links to other language editions")
+	clientLinksCmd.Flags().StringP("output", "o", string(output.FormatText), "output format: text, json, csv or yaml")
+	clientCmd.AddCommand(clientLinksCmd)
+
+	rootCmd.AddCommand(clientCmd)
+}
+
+func validateClientSearchInputs(cmd *cobra.Command, args []string) (err error) {
+	if _, err = output.ParseFormat(viper.GetString("output")); err != nil {
+		return
+	}
+	_, err = uintSetting("max-hops", 16)
+	return
+}
+
+func validateClientLinksInputs(cmd *cobra.Command, args []string) (err error) {
+	_, err = output.ParseFormat(viper.GetString("output"))
+	return
+}
+
+func runClientPathCommand(cmd *cobra.Command, args []string) {
+	client, closeClient := dialServer()
+	defer closeClient()
+	ctx, cancel := clientContext()
+	defer cancel()
+
+	result, err := client.FindPath(ctx, findPathRequest(cmd.Flags(), args))
+	if err != nil {
+		exitOnCallError(err, "Failed to search shortest path")
+	}
+	writeClientResult(result)
+}
+
+func runClientWatchCommand(cmd *cobra.Command, args []string) {
+	client, closeClient := dialServer()
+	defer closeClient()
+	ctx, cancel := clientContext()
+	defer cancel()
+
+	stream, err := client.WatchSearch(ctx, findPathRequest(cmd.Flags(), args))
+	if err != nil {
+		exitOnCallError(err, "Failed to search shortest path")
+	}
+
+	for {
+		update, err := stream.Recv()
+		switch {
+		case err == io.EOF:
+			exitOnCallError(errors.New("stream ended without result"), "Failed to search shortest path")
+		case err != nil:
+			exitOnCallError(err, "Failed to search shortest path")
+		case update.Result != nil:
+			writeClientResult(update.Result)
+			return
+		case update.Progress != nil:
+			logSearchProgress(update.Progress)
+		}
+	}
+}
+
+func runClientLinksCommand(cmd *cobra.Command, args []string) {
+	// the output format was validated by validateClientLinksInputs already
+	outputFormat, _ := output.ParseFormat(viper.GetString("output"))
+	client, closeClient := dialServer()
+	defer closeClient()
+	ctx, cancel := clientContext()
+	defer cancel()
+
+	request := &shortestpathv1.GetLinksRequest{
+		Title:         args[0],
+		Interlanguage: viper.GetBool("interlanguage"),
+		KeptOnly:      viper.GetBool("kept-only"),
+	}
+	request.Wiki, request.Lang = explicitWikiSelection(cmd.Flags())
+
+	response, err := client.GetLinks(ctx, request)
+	if err != nil {
+		exitOnCallError(err, "Failed to inspect links")
+	}
+
+	reports := make([]output.LinkReport, 0, len(response.Links))
+	for _, link := range response.Links {
+		reports = append(reports, output.LinkReport{
+			Kept:       link.Kept,
+			Reason:     link.Reason,
+			Region:     link.Region,
+			Section:    link.Section,
+			Namespace:  link.Namespace,
+			Kind:       link.Kind,
+			Title:      link.Title,
+			URL:        link.Url,
+			Href:       link.Href,
+			AnchorText: link.AnchorText,
+		})
+	}
+	if err = output.WriteLinks(os.Stdout, outputFormat, reports); err != nil {
+		log.
+			WithError(err).
+			Error("Failed to write links")
+		os.Exit(exitCodeError)
+	}
+}
+
+// dialServer connects to --server, the connection is established lazily by the first call
+func dialServer() (client shortestpathv1.ShortestPathClient, closeConn func()) {
+	conn, err := grpc.Dial(viper.GetString("server"), grpc.WithInsecure())
+	if err != nil {
+		log.
+			WithError(err).
+			Error("Failed to connect to server")
+		os.Exit(exitCodeError)
+	}
+	return shortestpathv1.NewShortestPathClient(conn), func() {
+		_ = conn.Close()
+	}
+}
+
+// clientContext limits the query to --timeout and cancels it on SIGINT or SIGTERM
+func clientContext() (ctx context.Context, cancel context.CancelFunc) {
+	if timeout := viper.GetDuration("timeout"); timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	go cancelOnSignal(ctx, cancel)
+	return
+}
+
+// explicitWikiSelection returns --wiki and --lang only if they are set explicitly so that the server applies its default wiki otherwise
+func explicitWikiSelection(flags *pflag.FlagSet) (wiki, lang string) {
+	if explicitlySet(viper.GetViper(), flags, "wiki") {
+		wiki = viper.GetString("wiki")
+	}
+	if explicitlySet(viper.GetViper(), flags, "lang") {
+		lang = viper.GetString("lang")
+	}
+	return
+}
+
+// findPathRequest builds the request of the path and watch commands, max hops were validated by validateClientSearchInputs already
+func findPathRequest(flags *pflag.FlagSet, args []string) *shortestpathv1.FindPathRequest {
+	hops, _ := uintSetting("max-hops", 16)
+	request := &shortestpathv1.FindPathRequest{
+		Start:         args[0],
+		Target:        args[1],
+		MaxHops:       uint32(hops),
+		Interlanguage: viper.GetBool("interlanguage"),
+		LinkContext:   viper.GetBool("link-context"),
+	}
+	request.Wiki, request.Lang = explicitWikiSelection(flags)
+	return request
+}
+
+// exitOnCallError exits with 64 if the server rejected the query, with 130 if it was interrupted and with 1 otherwise
+func exitOnCallError(err error, message string) {
+	exitCode := exitCodeError
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		exitCode = exitCodeUsage
+	case codes.Canceled:
+		exitCode = exitCodeInterrupted
+	}
+	log.
+		WithError(err).
+		Error(message)
+	os.Exit(exitCode)
+}
+
+// writeClientResult writes the result like the path command and exits with 2 if the target was not found
+func writeClientResult(message *shortestpathv1.SearchResult) {
+	// the output format was validated by validateClientSearchInputs already
+	outputFormat, _ := output.ParseFormat(viper.GetString("output"))
+	result := output.SearchResult{
+		Start:           message.Start,
+		Target:          message.Target,
+		Found:           message.Found,
+		Error:           message.Error,
+		Path:            make([]output.Page, 0, len(message.Path)),
+		Hops:            int(message.Hops),
+		DurationMillis:  message.DurationMs,
+		FetchedPages:    uint(message.FetchedPages),
+		DiscoveredPages: int(message.DiscoveredPages),
+	}
+	for _, page := range message.Path {
+		resultPage := output.Page{
+			Title:         page.Title,
+			URL:           page.Url,
+			Interlanguage: page.Interlanguage,
+		}
+		if page.Link != nil {
+			resultPage.Link = &output.Link{
+				AnchorText: page.Link.AnchorText,
+				Section:    page.Link.Section,
+				Snippet:    page.Link.Snippet,
+			}
+		}
+		result.Path = append(result.Path, resultPage)
+	}
+
+	if err := output.Write(os.Stdout, outputFormat, result); err != nil {
+		log.
+			WithError(err).
+			Error("Failed to write result")
+		os.Exit(exitCodeError)
+	}
+	if !result.Found {
+		os.Exit(exitCodeNotFound)
+	}
+}
+
+func logSearchProgress(progress *shortestpathv1.SearchProgress) {
+	switch progress.Type {
+	case "level_started":
+		log.Infof("Searching depth %d with %d pages", progress.Depth, progress.FrontierSize)
+	case "level_finished":
+		log.Infof("Finished depth %d, queued %d pages for the next level", progress.Depth, progress.NextFrontierSize)
+	case "page_processed":
+		log.Infof("Processed %d of %d pages of depth %d, fetched %d pages at %.1f pages per second",
+			progress.ProcessedPages, progress.FrontierSize, progress.Depth, progress.FetchedPages, progress.PagesPerSecond)
+	case "path_found":
+		log.Infof("Found path of %d pages after %d ms", len(progress.Path), progress.ElapsedMs)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	configEnvPrefix = "SHORTEST_PATH"
+	configDirName   = "shortest-path"
+	configFileName  = "config.yaml"
+	profilesKey     = "profiles"
+)
+
+var (
+	configEnvKeyReplacer = strings.NewReplacer("-", "_")
+)
+
+// defaultConfigPath returns $XDG_CONFIG_HOME/shortest-path/config.yaml, falling back to ~/.config if XDG_CONFIG_HOME is not set
+func defaultConfigPath() string {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, configDirName, configFileName)
+}
+
+// defaultDataPath returns the path of the file in $XDG_DATA_HOME/shortest-path, falling back to ~/.local/share if XDG_DATA_HOME is not set
+func defaultDataPath(fileName string) string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fileName
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, configDirName, fileName)
+}
+
+// loadConfig binds the flags and SHORTEST_PATH_* environment variables and reads the config file.
+// The config file is taken from --config or looked up at the default path, a missing default config is no error.
+// The settings of the profile selected by --profile or the profile key take precedence over the top level settings
+// of the config file but flags and environment variables still override both.
+func loadConfig(v *viper.Viper, flags *pflag.FlagSet) (err error) {
+	if err = v.BindPFlags(flags); err != nil {
+		return
+	}
+	v.SetEnvPrefix(configEnvPrefix)
+	v.SetEnvKeyReplacer(configEnvKeyReplacer)
+	v.AutomaticEnv()
+
+	configPath := v.GetString("config")
+	if configPath == "" {
+		if configPath = defaultConfigPath(); configPath == "" {
+			return
+		}
+		if _, statErr := os.Stat(configPath); statErr != nil {
+			configPath = ""
+		}
+	}
+
+	if configPath != "" {
+		v.SetConfigFile(configPath)
+		if err = v.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read config file %s: %w", configPath, err)
+		}
+	}
+
+	return applyProfile(v, v.GetString("profile"))
+}
+
+// applyProfile merges the settings of the named profile of the config file into the config, an empty name applies no profile
+func applyProfile(v *viper.Viper, name string) error {
+	if name == "" {
+		return nil
+	}
+
+	profiles := v.GetStringMap(profilesKey)
+	settings, ok := profiles[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("unknown profile %q, the config file defines %s", name, profileNames(profiles))
+	}
+
+	settingsMap, ok := settings.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("profile %q has to be a map of settings", name)
+	}
+	return v.MergeConfigMap(settingsMap)
+}
+
+func profileNames(profiles map[string]interface{}) string {
+	if len(profiles) == 0 {
+		return "no profiles"
+	}
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// explicitlySet checks whether the setting was given by a flag, an environment variable or the config file instead of a flag default
+func explicitlySet(v *viper.Viper, flags *pflag.FlagSet, key string) bool {
+	if flag := flags.Lookup(key); flag != nil && flag.Changed {
+		return true
+	}
+	if _, ok := os.LookupEnv(configEnvPrefix + "_" + strings.ToUpper(configEnvKeyReplacer.Replace(key))); ok {
+		return true
+	}
+	return v.InConfig(key)
+}
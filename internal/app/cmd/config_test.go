@@ -0,0 +1,168 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testConfig = `lang: de
+max-hops: 10
+profiles:
+  offline-en:
+    lang: en
+    spill-dir: /var/tmp/shortest-path
+  broken: 42
+`
+
+func testFlags() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("config", "", "")
+	flags.String("profile", "", "")
+	flags.String("lang", "en", "")
+	flags.String("spill-dir", "", "")
+	flags.String("max-hops", "20", "")
+	return flags
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "shortest-path-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := ioutil.WriteFile(configPath, []byte(testConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		env          map[string]string
+		wantLang     string
+		wantSpillDir string
+		wantMaxHops  int
+		wantErr      bool
+	}{
+		{
+			name:        "top level settings of config file",
+			args:        []string{"--config", configPath},
+			wantLang:    "de",
+			wantMaxHops: 10,
+		},
+		{
+			name:         "profile overrides top level settings",
+			args:         []string{"--config", configPath, "--profile", "offline-en"},
+			wantLang:     "en",
+			wantSpillDir: "/var/tmp/shortest-path",
+			wantMaxHops:  10,
+		},
+		{
+			name:         "flags override profile",
+			args:         []string{"--config", configPath, "--profile", "offline-en", "--lang", "fr"},
+			wantLang:     "fr",
+			wantSpillDir: "/var/tmp/shortest-path",
+			wantMaxHops:  10,
+		},
+		{
+			name:         "environment variables override config file",
+			args:         []string{"--config", configPath},
+			env:          map[string]string{"SHORTEST_PATH_MAX_HOPS": "6", "SHORTEST_PATH_PROFILE": "offline-en"},
+			wantLang:     "en",
+			wantSpillDir: "/var/tmp/shortest-path",
+			wantMaxHops:  6,
+		},
+		{
+			name:        "default config is optional",
+			env:         map[string]string{"XDG_CONFIG_HOME": filepath.Join(dir, "missing")},
+			wantLang:    "en",
+			wantMaxHops: 20,
+		},
+		{
+			name:    "unknown profile",
+			args:    []string{"--config", configPath, "--profile", "online"},
+			wantErr: true,
+		},
+		{
+			name:    "profile without settings",
+			args:    []string{"--config", configPath, "--profile", "broken"},
+			wantErr: true,
+		},
+		{
+			name:    "missing explicit config file",
+			args:    []string{"--config", filepath.Join(dir, "missing.yaml")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+				defer os.Unsetenv(key)
+			}
+
+			flags := testFlags()
+			if err := flags.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			v := viper.New()
+			if err := loadConfig(v, flags); (err != nil) != tt.wantErr {
+				t.Fatalf("loadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := v.GetString("lang"); got != tt.wantLang {
+				t.Errorf("lang = %s, want %s", got, tt.wantLang)
+			}
+			if got := v.GetString("spill-dir"); got != tt.wantSpillDir {
+				t.Errorf("spill-dir = %s, want %s", got, tt.wantSpillDir)
+			}
+			if got := v.GetInt("max-hops"); got != tt.wantMaxHops {
+				t.Errorf("max-hops = %d, want %d", got, tt.wantMaxHops)
+			}
+		})
+	}
+}
+
+func TestExplicitlySet(t *testing.T) {
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(os.TempDir(), "shortest-path-missing"))
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	flags := testFlags()
+	if err := flags.Parse([]string{"--lang", "de"}); err != nil {
+		t.Fatal(err)
+	}
+	v := viper.New()
+	if err := loadConfig(v, flags); err != nil {
+		t.Fatal(err)
+	}
+
+	if !explicitlySet(v, flags, "lang") {
+		t.Error("explicitlySet() = false for changed flag")
+	}
+	if explicitlySet(v, flags, "max-hops") {
+		t.Error("explicitlySet() = true for flag default")
+	}
+}
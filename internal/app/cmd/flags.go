@@ -0,0 +1,128 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"strconv"
+	"strings"
+)
+
+const (
+	exitCodeError       = 1
+	exitCodeNotFound    = 2
+	exitCodeUsage       = 64
+	exitCodeInterrupted = 130
+)
+
+// usageError marks errors caused by arguments or settings contradicting each other rather than by a failing setup
+type usageError struct {
+	error
+}
+
+func (err usageError) Unwrap() error {
+	return err.error
+}
+
+// logLevelValue is a flag accepting only the names of logrus levels
+type logLevelValue log.Level
+
+func (level *logLevelValue) String() string {
+	return log.Level(*level).String()
+}
+
+func (level *logLevelValue) Set(value string) error {
+	parsed, err := parseLogLevel(value)
+	if err != nil {
+		return err
+	}
+	*level = logLevelValue(parsed)
+	return nil
+}
+
+func (level *logLevelValue) Type() string {
+	return "level"
+}
+
+func parseLogLevel(value string) (level log.Level, err error) {
+	if level, err = log.ParseLevel(value); err != nil {
+		err = fmt.Errorf("invalid log level %q, use one of %s", value, strings.Join(logLevelNames(), ", "))
+	}
+	return
+}
+
+func logLevelNames() []string {
+	names := make([]string, 0, len(log.AllLevels))
+	for _, level := range log.AllLevels {
+		names = append(names, level.String())
+	}
+	return names
+}
+
+// uintSetting parses the setting as unsigned integer of the given bit size.
+// Values of the config file and environment variables are not checked by the typed flags and might be anything.
+func uintSetting(key string, bitSize int) (value uint64, err error) {
+	raw := viper.GetString(key)
+	if value, err = strconv.ParseUint(raw, 10, bitSize); err != nil {
+		err = fmt.Errorf("invalid value %q for %s, expected an integer between 0 and %d", raw, key, uint64(1)<<uint(bitSize)-1)
+	}
+	return
+}
+
+// maxHops returns the maximum depth of the search, at least one hop is required
+func maxHops() (hops uint16, err error) {
+	var value uint64
+	if value, err = uintSetting("max-hops", 16); err != nil {
+		return
+	}
+	if value == 0 {
+		return 0, errors.New("max-hops has to be at least 1")
+	}
+	return uint16(value), nil
+}
+
+// maxBranches returns the maximum number of branches per level of an exported search tree, 0 means all
+func maxBranches(key string) (branches int, err error) {
+	var value uint64
+	value, err = uintSetting(key, 31)
+	return int(value), err
+}
+
+// searchExitCode distinguishes a search which did not reach the target within max hops from failed searches
+func searchExitCode(err error, interrupted bool) int {
+	switch {
+	case interrupted:
+		return exitCodeInterrupted
+	case err == crawling.ErrMaxHopsReached:
+		return exitCodeNotFound
+	case err != nil:
+		return exitCodeError
+	default:
+		return 0
+	}
+}
+
+// setupExitCode exits with 64 if the crawler could not be set up because of its arguments and with 1 otherwise
+func setupExitCode(err error) int {
+	var usage usageError
+	if errors.As(err, &usage) {
+		return exitCodeUsage
+	}
+	return exitCodeError
+}
@@ -0,0 +1,183 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"testing"
+)
+
+func TestLogLevelValue_Set(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		wantLevel log.Level
+		wantErr   bool
+	}{
+		{
+			name:      "parse level",
+			value:     "debug",
+			wantLevel: log.DebugLevel,
+		},
+		{
+			name:      "parse level case insensitive",
+			value:     "WARN",
+			wantLevel: log.WarnLevel,
+		},
+		{
+			name:      "reject unknown level",
+			value:     "verbose",
+			wantLevel: log.InfoLevel,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level := logLevelValue(log.InfoLevel)
+			if err := level.Set(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if log.Level(level) != tt.wantLevel {
+				t.Errorf("Set() level = %v, want %v", log.Level(level), tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestMaxHops(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		wantHops uint16
+		wantErr  bool
+	}{
+		{
+			name:     "integer of config file",
+			value:    6,
+			wantHops: 6,
+		},
+		{
+			name:     "string of environment variable",
+			value:    "12",
+			wantHops: 12,
+		},
+		{
+			name:    "reject negative value",
+			value:   "-1",
+			wantErr: true,
+		},
+		{
+			name:    "reject overflow",
+			value:   70000,
+			wantErr: true,
+		},
+		{
+			name:    "reject zero hops",
+			value:   0,
+			wantErr: true,
+		},
+		{
+			name:    "reject garbage",
+			value:   "abc",
+			wantErr: true,
+		},
+	}
+	defer viper.Set("max-hops", nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("max-hops", tt.value)
+			gotHops, err := maxHops()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("maxHops() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotHops != tt.wantHops {
+				t.Errorf("maxHops() = %d, want %d", gotHops, tt.wantHops)
+			}
+		})
+	}
+}
+
+func TestSearchExitCode(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		interrupted bool
+		want        int
+	}{
+		{
+			name: "path found",
+			want: 0,
+		},
+		{
+			name: "target not reached",
+			err:  crawling.ErrMaxHopsReached,
+			want: exitCodeNotFound,
+		},
+		{
+			name: "search failed",
+			err:  errors.New("failed to create spill file"),
+			want: exitCodeError,
+		},
+		{
+			name:        "search interrupted",
+			err:         errors.New("context canceled"),
+			interrupted: true,
+			want:        exitCodeInterrupted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := searchExitCode(tt.err, tt.interrupted); got != tt.want {
+				t.Errorf("searchExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetupExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{
+			name: "setup failed",
+			err:  errors.New("failed to open page cache"),
+			want: exitCodeError,
+		},
+		{
+			name: "checkpoint of another search",
+			err:  usageError{errors.New("checkpoint search.ckpt belongs to search A -> B")},
+			want: exitCodeUsage,
+		},
+		{
+			name: "wrapped usage error",
+			err:  fmt.Errorf("resume: %w", usageError{errors.New("checkpoint search.ckpt belongs to search A -> B")}),
+			want: exitCodeUsage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := setupExitCode(tt.err); got != tt.want {
+				t.Errorf("setupExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"github.com/baez90/shortest-path/internal/app/export"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"os"
+)
+
+var (
+	graphCmd = &cobra.Command{
+		Use:     "graph <start> <target>",
+		Args:    validateArgs,
+		PreRunE: validateGraphInputs,
+		Short:   "Export the search tree explored while searching the shortest path",
+		Long: `Search the shortest path and write the explored search tree to stdout as Graphviz DOT or Mermaid graph.
+
+Every page is attached to the page it was discovered on first, the resolved path is highlighted.
+If no path is found the tree explored until the search gave up is written.`,
+		Run: runGraphCommand,
+	}
+)
+
+func init() {
+	addSearchFlags(graphCmd)
+	addCheckpointFlags(graphCmd)
+	graphCmd.Flags().String("format", string(export.GraphFormatDOT), "graph format: dot or mermaid")
+	graphCmd.Flags().Uint("max-branches", 0, "maximum number of branches per level in the search tree, 0 writes all")
+	rootCmd.AddCommand(graphCmd)
+}
+
+// validateGraphInputs validates the search and the graph settings
+func validateGraphInputs(cmd *cobra.Command, args []string) (err error) {
+	if err = validateSearchInputs(cmd, args); err != nil {
+		return
+	}
+	if _, err = export.ParseGraphFormat(viper.GetString("format")); err != nil {
+		return
+	}
+	_, err = maxBranches("max-branches")
+	return
+}
+
+func runGraphCommand(cmd *cobra.Command, args []string) {
+	// the settings were validated by validateGraphInputs already
+	format, _ := export.ParseGraphFormat(viper.GetString("format"))
+	branches, _ := maxBranches("max-branches")
+
+	crawler, err := setupCrawler(cmd.Flags(), args)
+	if err != nil {
+		log.
+			WithError(err).
+			Error("Failed to setup crawler")
+		os.Exit(setupExitCode(err))
+	}
+
+	res, duration, interrupted, err := runSearch(crawler)
+
+	exitCode := searchExitCode(err, interrupted)
+	switch {
+	case interrupted:
+		reportInterruptedSearch(crawler, duration)
+	case err != nil:
+		log.
+			WithError(err).
+			Error("Failed to resolve shortest path")
+	}
+
+	writer := bufio.NewWriter(os.Stdout)
+	writeErr := export.WriteGraph(writer, format, crawler.SearchTree(res, branches))
+	if writeErr == nil {
+		writeErr = writer.Flush()
+	}
+	if writeErr != nil {
+		log.
+			WithError(writeErr).
+			Error("Failed to write search tree")
+		exitCode = exitCodeError
+	}
+
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+}
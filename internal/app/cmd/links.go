@@ -0,0 +1,127 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"github.com/baez90/shortest-path/internal/app/output"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"os"
+)
+
+var (
+	linksCmd = &cobra.Command{
+		Use:     "links <page>",
+		Args:    validateLinksArgs,
+		PreRunE: validateLinksInputs,
+		Short:   "Explain which links of a page the search follows",
+		Long: `Fetch a page, or read it from a local file with --file, and list every link on it
+with its region, section and namespace and the reason why the search follows or ignores it.
+
+The page is given either as article URL or as title, it determines the wiki and the site profile to apply.
+With --file the page argument is optional, the file is then parsed as page of the wiki selected by --wiki or --lang.`,
+		Run: runLinksCommand,
+	}
+)
+
+func init() {
+	linksCmd.Flags().String("file", "", "local HTML file to read the page from instead of fetching it")
+	linksCmd.Flags().Bool("kept-only", false, "list only the links the search follows")
+	linksCmd.Flags().Bool("interlanguage", false, "follow interlanguage links to other language editions")
+	linksCmd.Flags().StringP("output", "o", string(output.FormatText), "output format: text, json, csv or yaml")
+	rootCmd.AddCommand(linksCmd)
+}
+
+// validateLinksArgs requires the page unless it is read from a file
+func validateLinksArgs(cmd *cobra.Command, args []string) error {
+	if file, _ := cmd.Flags().GetString("file"); file != "" {
+		return cobra.MaximumNArgs(1)(cmd, args)
+	}
+	return cobra.ExactArgs(1)(cmd, args)
+}
+
+// validateLinksInputs validates the output format and resolves the page before it is fetched
+func validateLinksInputs(cmd *cobra.Command, args []string) (err error) {
+	if _, err = output.ParseFormat(viper.GetString("output")); err != nil {
+		return
+	}
+	_, err = resolveInspectedPage(args)
+	return
+}
+
+func runLinksCommand(cmd *cobra.Command, args []string) {
+	// output format and page were validated by validateLinksInputs already
+	outputFormat, _ := output.ParseFormat(viper.GetString("output"))
+	page, _ := resolveInspectedPage(args)
+
+	body, err := openInspectedPage(page)
+	if err != nil {
+		log.
+			WithError(err).
+			Error("Failed to read page")
+		os.Exit(exitCodeError)
+	}
+	defer body.Close()
+
+	decisions, err := crawling.InspectLinks(body, page, viper.GetBool("interlanguage"))
+	if err != nil {
+		log.
+			WithError(err).
+			Error("Failed to parse page")
+		os.Exit(exitCodeError)
+	}
+
+	writer := bufio.NewWriter(os.Stdout)
+	err = output.WriteLinks(writer, outputFormat, output.NewLinkReports(decisions, viper.GetBool("kept-only")))
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil {
+		log.
+			WithError(err).
+			Error("Failed to write links")
+		os.Exit(exitCodeError)
+	}
+}
+
+// resolveInspectedPage resolves the page argument, without argument the page is an untitled page of the selected wiki
+func resolveInspectedPage(args []string) (page crawling.PageReference, err error) {
+	var defaultWiki string
+	var profiles crawling.SiteProfiles
+	if defaultWiki, profiles, err = wikiSelection(); err != nil {
+		return
+	}
+
+	if len(args) == 1 {
+		return crawling.ResolvePage(args[0], defaultWiki, profiles)
+	}
+
+	page.WikiBase = defaultWiki
+	page.Site, err = profiles.ForURL(defaultWiki)
+	return
+}
+
+func openInspectedPage(page crawling.PageReference) (io.ReadCloser, error) {
+	if file := viper.GetString("file"); file != "" {
+		return os.Open(file)
+	}
+	log.Infof("Fetching %s", page.URI())
+	return crawling.FetchPage(context.Background(), page.URI())
+}
@@ -0,0 +1,114 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/baez90/shortest-path/internal/app/export"
+	"github.com/baez90/shortest-path/internal/app/output"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"os"
+)
+
+var (
+	pathCmd = &cobra.Command{
+		Use:     "path <start> <target>",
+		Args:    validateArgs,
+		PreRunE: validatePathInputs,
+		Short:   "Search the shortest path between two articles",
+		Long: `Search the shortest path of links from the start article to the target article.
+
+The search can be interrupted with SIGINT, with --checkpoint it can be resumed later on with --resume.`,
+		Run: runPathCommand,
+	}
+)
+
+func init() {
+	addSearchFlags(pathCmd)
+	addCheckpointFlags(pathCmd)
+	addPathOutputFlags(pathCmd)
+	rootCmd.AddCommand(pathCmd)
+}
+
+func addPathOutputFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("output", "o", string(output.FormatText), "output format of the result: text, json, csv or yaml")
+	cmd.Flags().String("export-graph", "", "file to export the explored search tree to, .dot/.gv for Graphviz or .mmd for Mermaid")
+	cmd.Flags().Uint("export-max-branches", 0, "maximum number of branches per level in the exported search tree, 0 exports all")
+}
+
+// validatePathInputs validates the search and its output settings
+func validatePathInputs(cmd *cobra.Command, args []string) (err error) {
+	if err = validateSearchInputs(cmd, args); err != nil {
+		return
+	}
+	if _, err = output.ParseFormat(viper.GetString("output")); err != nil {
+		return
+	}
+	if graphFile := viper.GetString("export-graph"); graphFile != "" {
+		if _, err = export.GraphFormatForFile(graphFile); err != nil {
+			return
+		}
+	}
+	_, err = maxBranches("export-max-branches")
+	return
+}
+
+func runPathCommand(cmd *cobra.Command, args []string) {
+	// the settings were validated by validatePathInputs already
+	outputFormat, _ := output.ParseFormat(viper.GetString("output"))
+	graphFile := viper.GetString("export-graph")
+	exportMaxBranches, _ := maxBranches("export-max-branches")
+
+	crawler, err := setupCrawler(cmd.Flags(), args)
+	if err != nil {
+		log.
+			WithError(err).
+			Error("Failed to setup crawler")
+		os.Exit(setupExitCode(err))
+	}
+
+	res, duration, interrupted, err := runSearch(crawler)
+
+	exitCode := searchExitCode(err, interrupted)
+	switch {
+	case interrupted:
+		reportInterruptedSearch(crawler, duration)
+	case err != nil:
+		log.
+			WithError(err).
+			Error("Failed to resolve shortest path")
+	}
+
+	if writeErr := output.Write(os.Stdout, outputFormat, output.NewSearchResult(crawler, res, err, duration)); writeErr != nil {
+		log.
+			WithError(writeErr).
+			Error("Failed to write result")
+		exitCode = exitCodeError
+	}
+
+	if graphFile != "" {
+		if exportErr := export.WriteGraphFile(graphFile, crawler.SearchTree(res, exportMaxBranches)); exportErr != nil {
+			log.
+				WithError(exportErr).
+				Error("Failed to export search tree")
+			exitCode = exitCodeError
+		}
+	}
+
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+}
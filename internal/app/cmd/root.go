@@ -15,37 +15,89 @@
 package cmd
 
 import (
-	"github.com/baez90/shortest-path/internal/app/crawling"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"os"
-	"time"
+	"strings"
 )
 
 var (
 	rootCmd = &cobra.Command{
-		Use:   "shortest-path",
-		Args:  cobra.ExactArgs(2),
-		Short: "",
-		Long:  ``,
-		Run:   runTraverseCommand,
+		Use:     "shortest-path <start> <target>",
+		Args:    validateRootArgs,
+		PreRunE: validatePathInputs,
+		Short:   "Find the shortest path of links between two wiki articles",
+		Long: `shortest-path searches the shortest chain of links leading from one wiki article to another.
+
+Pages are given either as article URLs or as titles resolved in the wiki selected by --wiki or --lang.
+Calling shortest-path with a start and a target page is a shortcut for the path command.
+
+Every flag can also be set in the config file or as environment variable prefixed with SHORTEST_PATH_
+e.g. SHORTEST_PATH_MAX_HOPS=6. Flags take precedence over environment variables, environment variables over
+the config file. Named profiles of the config file bundle settings and override its top level settings:
+
+  lang: en
+  profiles:
+    offline-en:
+      wiki: https://en.wikipedia.org
+      spill-dir: /var/tmp/shortest-path
+      interlanguage: false
+
+Searches are traced with OpenTelemetry if --trace-otlp-endpoint or --trace-file is set: every search, BFS level
+and processed page is recorded as a span, fetching and parsing a page as its child spans.
+
+Exit codes:
+  0    the path was found or the command succeeded
+  1    the command failed e.g. because a page could not be fetched
+  2    no path was found within --max-hops
+  64   invalid arguments, flags or settings
+  130  the search was interrupted`,
+		PersistentPreRunE: initConfig,
+		Run:               runRootCommand,
 	}
 )
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		log.WithError(err).Error("failed to execute command")
-		os.Exit(1)
+		os.Exit(exitCodeUsage)
 	}
 }
 
 func init() {
 	cobra.OnInitialize(initLogging)
-	cobra.OnInitialize(initConfig)
 
-	rootCmd.PersistentFlags().String("max-hops", "20", "depth of the search")
-	rootCmd.PersistentFlags().String("log-level", "info", "log level to use")
+	rootCmd.PersistentFlags().String("config", "", "config file to read, defaults to $XDG_CONFIG_HOME/shortest-path/config.yaml if it exists")
+	rootCmd.PersistentFlags().String("profile", "", "named profile of the config file to apply on top of its top level settings")
+	logLevel := logLevelValue(log.InfoLevel)
+	rootCmd.PersistentFlags().Var(&logLevel, "log-level", "log level to use: "+strings.Join(logLevelNames(), ", "))
+	rootCmd.PersistentFlags().String("lang", "en", "language of the Wikipedia to resolve page titles in")
+	rootCmd.PersistentFlags().String("wiki", "", "base URL of the wiki to resolve page titles in e.g. https://en.wiktionary.org, takes precedence over --lang")
+	rootCmd.PersistentFlags().String("site-profiles", "", "YAML file with additional site profiles describing the URL layout and page structure of custom wikis")
+	rootCmd.PersistentFlags().String("trace-otlp-endpoint", "", "address of an OpenTelemetry collector to export spans of searches to via OTLP over gRPC e.g. localhost:55680")
+	rootCmd.PersistentFlags().String("trace-file", "", "file to append spans of searches to as JSON objects, one per line")
+
+	// the root command keeps searching paths on its own to stay compatible with scripts calling it directly
+	addSearchFlags(rootCmd)
+	addCheckpointFlags(rootCmd)
+	addPathOutputFlags(rootCmd)
+}
+
+// validateRootArgs allows calling the root command without any arguments to show the usage
+func validateRootArgs(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	return validateArgs(cmd, args)
+}
+
+func runRootCommand(cmd *cobra.Command, args []string) {
+	if resume, _ := cmd.Flags().GetString("resume"); len(args) == 0 && resume == "" {
+		_ = cmd.Help()
+		return
+	}
+	runPathCommand(cmd, args)
 }
 
 func initLogging() {
@@ -56,35 +108,16 @@ func initLogging() {
 	log.SetLevel(log.InfoLevel)
 }
 
-func initConfig() {
-	if err := viper.BindPFlags(rootCmd.PersistentFlags()); err != nil {
-		log.WithError(err).Error("failed to bind flags to viper")
+// initConfig binds the flags of the executed command to viper and reads the config file, all commands share the same keys
+func initConfig(cmd *cobra.Command, args []string) error {
+	if err := loadConfig(viper.GetViper(), cmd.Flags()); err != nil {
+		return err
 	}
 
-	if level, err := log.ParseLevel(viper.GetString("log-level")); err != nil {
-		log.WithError(err).Error("failed to parse log level")
-	} else {
-		log.SetLevel(level)
-	}
-}
-
-func runTraverseCommand(cmd *cobra.Command, args []string) {
-
-	crawler := crawling.NewWikiCrawler(args[0], args[1], uint16(viper.GetInt("max-hops")))
-
-	start := time.Now()
-	if res, err := crawler.SearchShortestPath(); err != nil {
-		log.
-			WithError(err).
-			Error("Failed to resolve shortest path")
-		os.Exit(2)
-	} else {
-		duration := time.Since(start)
-		log.Infof("Resolved path in %d ms", duration.Milliseconds())
-		for _, visitedPage := range res.VisitedPages() {
-			log.Info(visitedPage)
-		}
-		log.Infof("Visited %d pages to find path", crawler.FetchedPages())
-		log.Infof("Discovered %d unique links during search", crawler.DiscoveredPages())
+	level, err := parseLogLevel(viper.GetString("log-level"))
+	if err != nil {
+		return err
 	}
+	log.SetLevel(level)
+	return nil
 }
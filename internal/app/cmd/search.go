@@ -0,0 +1,315 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/baez90/shortest-path/internal/app/metrics"
+	"github.com/baez90/shortest-path/internal/app/tracing"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+var (
+	// searchMetrics accumulates the metrics of all searches of the process if --metrics-file is set e.g. of all runs of bench
+	searchMetrics *metrics.Metrics
+	// searchTracing records the spans of the searches set up since it was opened, nil if tracing is disabled
+	searchTracing *tracing.Tracing
+)
+
+// addSearchFlags adds the flags configuring the crawler to all commands running a search
+func addSearchFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint16("max-hops", 20, "depth of the search, at least 1")
+	cmd.Flags().Uint64("frontier-memory-limit", 256<<20, "bytes of queued pages per BFS level to keep in memory before spilling to disk, 0 disables spilling")
+	cmd.Flags().String("spill-dir", "", "directory to spill frontier segments to, defaults to the temporary directory")
+	cmd.Flags().Bool("interlanguage", false, "follow interlanguage links to other language editions, start and target may belong to different languages")
+	cmd.Flags().Bool("link-context", false, "capture anchor text, section and sentence of every link on the path, increases memory usage")
+	cmd.Flags().String("metrics-file", "", "file to write Prometheus metrics of the search to after it finished, for the textfile collector of the node exporter")
+}
+
+// addCheckpointFlags adds the flags to persist and resume the progress of a single search
+func addCheckpointFlags(cmd *cobra.Command) {
+	cmd.Flags().String("checkpoint", "", "file to periodically persist the search progress to")
+	cmd.Flags().Duration("checkpoint-interval", 5*time.Minute, "minimum time between two checkpoints")
+	cmd.Flags().String("resume", "", "checkpoint file to resume a previous search from")
+}
+
+// validateSearchInputs validates the settings of the search and resolves its pages before any page is fetched
+func validateSearchInputs(cmd *cobra.Command, args []string) (err error) {
+	if _, err = maxHops(); err != nil {
+		return
+	}
+	if _, err = uintSetting("frontier-memory-limit", 64); err != nil {
+		return
+	}
+	if cmd.Flags().Lookup("checkpoint-interval") != nil && viper.GetDuration("checkpoint-interval") <= 0 {
+		return fmt.Errorf("checkpoint-interval has to be a positive duration e.g. 5m")
+	}
+
+	if len(args) == 2 {
+		_, _, err = resolvePages(cmd.Flags(), args)
+	} else {
+		_, _, err = wikiSelection()
+	}
+	return
+}
+
+// validateArgs requires start and target page unless a search is resumed from a checkpoint
+func validateArgs(cmd *cobra.Command, args []string) error {
+	if resume, _ := cmd.Flags().GetString("resume"); resume != "" && len(args) == 0 {
+		return nil
+	}
+	return cobra.ExactArgs(2)(cmd, args)
+}
+
+// runSearch searches the shortest path with the given crawler until it is done or interrupted by SIGINT or SIGTERM
+func runSearch(crawler *crawling.WikiCrawler) (res crawling.TraversalResult, duration time.Duration, interrupted bool, err error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cancelOnSignal(ctx, cancel)
+
+	defer closeSearchTracing()
+
+	start := time.Now()
+	res, err = crawler.SearchShortestPath(ctx)
+	duration = time.Since(start)
+	interrupted = err != nil && ctx.Err() != nil
+
+	writeMetricsFile()
+	return
+}
+
+// tracingConfig selects the exporters of spans by --trace-otlp-endpoint and --trace-file
+func tracingConfig() tracing.Config {
+	return tracing.Config{
+		OTLPEndpoint: viper.GetString("trace-otlp-endpoint"),
+		File:         viper.GetString("trace-file"),
+	}
+}
+
+// openTracing sets up the exporters of spans selected by tracingConfig,
+// it returns nil if tracing is disabled or failed to set up in which case searches run without tracing
+func openTracing() *tracing.Tracing {
+	config := tracingConfig()
+	if !config.Enabled() {
+		return nil
+	}
+	searchTracing, err := tracing.Open(config)
+	if err != nil {
+		log.
+			WithError(err).
+			Warn("Failed to set up tracing, searching without tracing")
+		return nil
+	}
+	return searchTracing
+}
+
+// writeMetricsFile writes the metrics of all searches so far to --metrics-file if it is set
+func writeMetricsFile() {
+	metricsFile := viper.GetString("metrics-file")
+	if metricsFile == "" || searchMetrics == nil {
+		return
+	}
+	if err := searchMetrics.WriteTextfile(metricsFile); err != nil {
+		log.
+			WithError(err).
+			Warnf("Failed to write metrics to %s", metricsFile)
+	}
+}
+
+// closeSearchTracing exports the spans of the searches set up so far, the following searches open tracing again
+func closeSearchTracing() {
+	if searchTracing != nil {
+		closeTracing(searchTracing)
+		searchTracing = nil
+	}
+}
+
+// closeTracing exports the pending spans before the process exits
+func closeTracing(searchTracing *tracing.Tracing) {
+	if err := searchTracing.Close(); err != nil {
+		log.
+			WithError(err).
+			Warn("Failed to export spans")
+	}
+}
+
+// searchOptions configures a crawler by the search flags shared by all commands running a search
+func searchOptions() (options []crawling.Option, err error) {
+	var hops uint16
+	if hops, err = maxHops(); err != nil {
+		return
+	}
+	options = []crawling.Option{
+		crawling.WithMaxHops(hops),
+		crawling.WithFrontierSpilling(viper.GetString("spill-dir"), viper.GetUint64("frontier-memory-limit")),
+	}
+	if viper.GetBool("link-context") {
+		options = append(options, crawling.WithLinkContext())
+	}
+	if viper.GetBool("interlanguage") {
+		options = append(options, crawling.WithInterlanguageLinks())
+	}
+	if viper.GetString("metrics-file") != "" {
+		if searchMetrics == nil {
+			searchMetrics = metrics.New()
+		}
+		options = append(options, searchMetrics.CrawlerOptions()...)
+	}
+	if searchTracing == nil {
+		searchTracing = openTracing()
+	}
+	if searchTracing != nil {
+		options = append(options, crawling.WithTracer(searchTracing.Tracer()))
+	}
+	return
+}
+
+func setupCrawler(flags *pflag.FlagSet, args []string) (crawler *crawling.WikiCrawler, err error) {
+	var options []crawling.Option
+	if options, err = searchOptions(); err != nil {
+		return
+	}
+
+	checkpointPath := viper.GetString("checkpoint")
+	resumePath := viper.GetString("resume")
+	if checkpointPath == "" {
+		checkpointPath = resumePath
+	}
+	if checkpointPath != "" {
+		options = append(options, crawling.WithCheckpoints(checkpointPath, viper.GetDuration("checkpoint-interval")))
+	}
+
+	if resumePath != "" {
+		if crawler, err = crawling.ResumeWikiCrawler(resumePath, options...); err != nil {
+			return
+		}
+		if len(args) == 2 {
+			var start, target crawling.PageReference
+			if start, target, err = resolvePages(flags, args); err != nil {
+				return
+			}
+			if start.URI() != crawler.StartPage() || target.URI() != crawler.TargetPage() {
+				err = usageError{fmt.Errorf("checkpoint %s belongs to search %s -> %s", resumePath, crawler.StartPage(), crawler.TargetPage())}
+				return
+			}
+		}
+		log.Infof("Resuming search %s -> %s from %s", crawler.StartPage(), crawler.TargetPage(), resumePath)
+	} else {
+		var start, target crawling.PageReference
+		if start, target, err = resolvePages(flags, args); err != nil {
+			return
+		}
+		crawler = crawling.NewWikiCrawlerForPages(start, target, options...)
+	}
+	return
+}
+
+// resolvePages resolves the start and target arguments with the resolver of pageResolver
+func resolvePages(flags *pflag.FlagSet, args []string) (start, target crawling.PageReference, err error) {
+	var resolve func(start, target string) (crawling.PageReference, crawling.PageReference, error)
+	if resolve, err = pageResolver(flags); err != nil {
+		return
+	}
+	return resolve(args[0], args[1])
+}
+
+// pageResolver returns a function resolving start and target, titles are resolved in the wiki selected by --wiki or --lang.
+// With --interlanguage the pages may belong to different language editions.
+func pageResolver(flags *pflag.FlagSet) (resolver func(start, target string) (crawling.PageReference, crawling.PageReference, error), err error) {
+	var defaultWiki string
+	var profiles crawling.SiteProfiles
+	if defaultWiki, profiles, err = wikiSelection(); err != nil {
+		return
+	}
+
+	resolve := crawling.ResolvePages
+	if viper.GetBool("interlanguage") {
+		resolve = crawling.ResolvePagesAcrossWikis
+	}
+	explicitWiki := explicitlySet(viper.GetViper(), flags, "wiki") || explicitlySet(viper.GetViper(), flags, "lang")
+
+	resolver = func(startArg, targetArg string) (start, target crawling.PageReference, err error) {
+		if start, target, err = resolve(startArg, targetArg, defaultWiki, profiles); err != nil {
+			return
+		}
+		if explicitWiki && !crawling.SameWiki(start.WikiBase, defaultWiki) {
+			err = fmt.Errorf("pages belong to %s but %s was selected by --wiki or --lang", start.WikiBase, defaultWiki)
+		}
+		return
+	}
+	return
+}
+
+// wikiSelection returns the wiki selected by --wiki or --lang and the site profiles to resolve pages with
+func wikiSelection() (defaultWiki string, profiles crawling.SiteProfiles, err error) {
+	if wiki := viper.GetString("wiki"); wiki != "" {
+		defaultWiki, err = crawling.ParseWikiBaseURL(wiki)
+	} else {
+		defaultWiki, err = crawling.WikipediaBaseURL(viper.GetString("lang"))
+	}
+
+	if err != nil {
+		return
+	}
+
+	profiles, err = siteProfiles()
+	return
+}
+
+// siteProfiles returns the built-in site profiles extended by the profiles of --site-profiles
+func siteProfiles() (crawling.SiteProfiles, error) {
+	if path := viper.GetString("site-profiles"); path != "" {
+		return crawling.LoadSiteProfiles(path)
+	}
+	return crawling.BuiltinSiteProfiles(), nil
+}
+
+// cancelOnSignal cancels the search on SIGINT or SIGTERM.
+// Afterwards the default signal handling is restored so that a second signal terminates the process immediately.
+func cancelOnSignal(ctx context.Context, cancel context.CancelFunc) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case sig := <-signals:
+		log.Warnf("Received %s, stopping search", sig)
+		cancel()
+	case <-ctx.Done():
+	}
+}
+
+func reportInterruptedSearch(crawler *crawling.WikiCrawler, duration time.Duration) {
+	progress := crawler.Progress()
+	log.Warnf("Search interrupted after %d ms", duration.Milliseconds())
+	log.Infof("Reached depth %d, processed %d of %d pages of the current level", progress.Depth, progress.ProcessedPages, progress.FrontierSize)
+	log.Infof("Queued %d pages for the next level", progress.NextFrontierSize)
+	log.Infof("Visited %d pages", progress.FetchedPages)
+	log.Infof("Discovered %d unique links during search", progress.DiscoveredPages)
+	if progress.RuledOutHops() > 0 {
+		log.Infof("Target is not reachable within %d hops", progress.RuledOutHops())
+	} else {
+		log.Info("Target could not be ruled out at any depth yet")
+	}
+}
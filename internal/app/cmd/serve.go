@@ -0,0 +1,257 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"github.com/baez90/shortest-path/internal/app/jobs"
+	"github.com/baez90/shortest-path/internal/app/metrics"
+	"github.com/baez90/shortest-path/internal/app/server"
+	"github.com/baez90/shortest-path/internal/app/tracing"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const (
+	serverShutdownTimeout = 30 * time.Second
+	jobStoreFileName      = "jobs.db"
+)
+
+var (
+	serveCmd = &cobra.Command{
+		Use:     "serve",
+		Args:    cobra.NoArgs,
+		PreRunE: validateServeInputs,
+		Short:   "Answer path and link queries via an HTTP JSON API",
+		Long: `Run an HTTP server answering path and link queries:
+
+  POST /v1/paths          search the shortest path, the body is a JSON object with start and target
+                          and the optional fields wiki, lang, max_hops, interlanguage and link_context
+  GET  /v1/links/{title}  explain which links of the page the search follows,
+                          the query parameters wiki, lang, interlanguage and kept_only are optional
+  POST /v1/jobs           submit a search with the same body as POST /v1/paths to run in the background
+  GET  /v1/jobs/{id}      poll state, depth, fetched and discovered pages and finally the result of a job
+  DELETE /v1/jobs/{id}    cancel a queued or running job or delete a finished one
+  GET  /v1/jobs/{id}/events
+                          stream the progress of a job as Server-Sent Events: level_started, level_finished,
+                          page_processed and path_found with depth, frontier sizes and pages per second
+  GET  /metrics           Prometheus metrics of page retrievals, the page and link caches and searches
+
+With --grpc-listen the same queries are answered via gRPC as well, the service is defined by
+api/shortestpath/v1/shortest_path.proto: FindPath, GetLinks and WatchSearch streaming the progress of a search.
+The client command queries this service.
+
+Every query runs its own search, the links of processed pages and the fetched pages are shared between all queries
+by in-memory caches limited by --link-cache-size and --cache-size.
+--max-hops is the default and the upper limit of every search.
+Jobs and their results are persisted to --job-store and survive restarts, jobs interrupted by a restart fail.`,
+		Run: runServeCommand,
+	}
+)
+
+func init() {
+	serveCmd.Flags().String("listen", ":8080", "address to listen on")
+	serveCmd.Flags().String("grpc-listen", "", "address to answer gRPC queries on, the gRPC API is disabled if empty")
+	serveCmd.Flags().Uint16("max-hops", 20, "default and maximum depth of every search, at least 1")
+	serveCmd.Flags().Uint64("frontier-memory-limit", 256<<20, "bytes of queued pages per BFS level and search to keep in memory before spilling to disk, 0 disables spilling")
+	serveCmd.Flags().String("spill-dir", "", "directory to spill frontier segments to, defaults to the temporary directory")
+	serveCmd.Flags().Uint64("cache-size", 256<<20, "bytes of fetched pages to keep in memory and share between all queries")
+	serveCmd.Flags().Uint64("link-cache-size", 256<<20, "bytes of links of processed pages to keep in memory and share between all searches, 0 disables sharing links")
+	serveCmd.Flags().Duration("search-timeout", 5*time.Minute, "maximum duration of a single search of POST /v1/paths, 0 disables the limit")
+	serveCmd.Flags().String("job-store", "", "file to persist jobs to, defaults to $XDG_DATA_HOME/shortest-path/"+jobStoreFileName)
+	serveCmd.Flags().Uint("job-workers", 2, "number of jobs running at the same time, at least 1")
+	serveCmd.Flags().Uint("job-queue-size", 100, "number of jobs waiting for a worker before further jobs are rejected")
+	rootCmd.AddCommand(serveCmd)
+}
+
+// validateServeInputs validates the defaults of all queries before the server starts listening
+func validateServeInputs(cmd *cobra.Command, args []string) (err error) {
+	if _, err = maxHops(); err != nil {
+		return
+	}
+	if _, err = uintSetting("frontier-memory-limit", 64); err != nil {
+		return
+	}
+	if _, err = uintSetting("cache-size", 64); err != nil {
+		return
+	}
+	if _, err = uintSetting("link-cache-size", 64); err != nil {
+		return
+	}
+	if _, err = uintSetting("job-queue-size", 31); err != nil {
+		return
+	}
+	if workers, workersErr := uintSetting("job-workers", 16); workersErr != nil {
+		return workersErr
+	} else if workers == 0 {
+		return errors.New("job-workers has to be at least 1")
+	}
+	_, _, err = wikiSelection()
+	return
+}
+
+func runServeCommand(cmd *cobra.Command, args []string) {
+	// the settings were validated by validateServeInputs already
+	defaultWiki, profiles, _ := wikiSelection()
+	hops, _ := maxHops()
+	frontierMemoryLimit, _ := uintSetting("frontier-memory-limit", 64)
+	cacheSize, _ := uintSetting("cache-size", 64)
+	linkCacheSize, _ := uintSetting("link-cache-size", 64)
+	jobWorkers, _ := uintSetting("job-workers", 16)
+	jobQueueSize, _ := uintSetting("job-queue-size", 31)
+
+	jobStorePath := viper.GetString("job-store")
+	if jobStorePath == "" {
+		jobStorePath = defaultDataPath(jobStoreFileName)
+	}
+	store, err := jobs.OpenStore(jobStorePath)
+	if err != nil {
+		log.
+			WithError(err).
+			Error("Failed to open job store")
+		os.Exit(exitCodeError)
+	}
+	defer store.Close()
+
+	manager, err := jobs.NewManager(store, int(jobWorkers), int(jobQueueSize))
+	if err != nil {
+		log.
+			WithError(err).
+			Error("Failed to restore jobs")
+		os.Exit(exitCodeError)
+	}
+	defer manager.Close()
+
+	serverMetrics := metrics.New()
+	cache := crawling.NewPageCache(crawling.NewPageFetcher(serverMetrics.HTTPClient()), cacheSize)
+	serverMetrics.RegisterPageCache(cache)
+
+	handler := server.NewServer(server.Config{
+		DefaultWiki:         defaultWiki,
+		SiteProfiles:        profiles,
+		MaxHops:             hops,
+		SpillDirectory:      viper.GetString("spill-dir"),
+		FrontierMemoryLimit: frontierMemoryLimit,
+		SearchTimeout:       viper.GetDuration("search-timeout"),
+		LinkCacheSize:       linkCacheSize,
+	}, cache)
+	handler.EnableJobs(manager)
+	handler.EnableMetrics(serverMetrics)
+
+	if config := tracingConfig(); config.Enabled() {
+		serverTracing, err := tracing.Open(config)
+		if err != nil {
+			log.
+				WithError(err).
+				Error("Failed to set up tracing")
+			manager.Close()
+			store.Close()
+			os.Exit(exitCodeError)
+		}
+		defer closeTracing(serverTracing)
+		handler.EnableTracing(serverTracing.Tracer())
+	}
+
+	var grpcServer *grpc.Server
+	if grpcAddress := viper.GetString("grpc-listen"); grpcAddress != "" {
+		grpcListener, err := net.Listen("tcp", grpcAddress)
+		if err != nil {
+			log.
+				WithError(err).
+				Error("Failed to listen for gRPC queries")
+			manager.Close()
+			store.Close()
+			os.Exit(exitCodeError)
+		}
+		grpcServer = grpc.NewServer()
+		handler.RegisterGRPC(grpcServer)
+		go func() {
+			log.Infof("Answering gRPC queries on %s", grpcListener.Addr())
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				log.
+					WithError(err).
+					Error("Failed to serve gRPC queries")
+			}
+		}()
+	}
+
+	httpServer := &http.Server{
+		Addr:    viper.GetString("listen"),
+		Handler: handler,
+	}
+
+	stopped := make(chan struct{})
+	go shutdownOnSignal(httpServer, grpcServer, stopped)
+
+	log.Infof("Listening on %s, persisting jobs to %s", httpServer.Addr, jobStorePath)
+	if err = httpServer.ListenAndServe(); err != http.ErrServerClosed {
+		log.
+			WithError(err).
+			Error("Failed to serve")
+		manager.Close()
+		store.Close()
+		os.Exit(exitCodeError)
+	}
+	<-stopped
+}
+
+// shutdownOnSignal stops accepting queries on SIGINT or SIGTERM and waits for running queries to finish.
+// Running jobs are cancelled afterwards when the job manager is closed, grpcServer is nil if gRPC is disabled.
+func shutdownOnSignal(httpServer *http.Server, grpcServer *grpc.Server, stopped chan<- struct{}) {
+	defer close(stopped)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	sig := <-signals
+	signal.Stop(signals)
+
+	log.Warnf("Received %s, shutting down", sig)
+	ctx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		log.
+			WithError(err).
+			Warn("Failed to shut down gracefully")
+	}
+	if grpcServer != nil {
+		stopGRPCServer(ctx, grpcServer)
+	}
+}
+
+// stopGRPCServer waits for running gRPC calls to finish until ctx is done and aborts them afterwards
+func stopGRPCServer(ctx context.Context, grpcServer *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		log.Warn("Failed to shut down gRPC server gracefully")
+		grpcServer.Stop()
+	}
+}
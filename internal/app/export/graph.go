@@ -0,0 +1,189 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type GraphFormat string
+
+const (
+	GraphFormatDOT     GraphFormat = "dot"
+	GraphFormatMermaid GraphFormat = "mermaid"
+)
+
+var (
+	graphFormats            = []GraphFormat{GraphFormatDOT, GraphFormatMermaid}
+	graphFormatsByExtension = map[string]GraphFormat{
+		".dot":     GraphFormatDOT,
+		".gv":      GraphFormatDOT,
+		".mmd":     GraphFormatMermaid,
+		".mermaid": GraphFormatMermaid,
+	}
+	dotEscaper     = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	mermaidEscaper = strings.NewReplacer(`"`, `#quot;`)
+)
+
+// ParseGraphFormat parses the name of a graph format case insensitively
+func ParseGraphFormat(value string) (format GraphFormat, err error) {
+	for _, format = range graphFormats {
+		if strings.EqualFold(string(format), value) {
+			return
+		}
+	}
+	err = fmt.Errorf("unknown graph format %s, supported formats are %v", value, graphFormats)
+	return
+}
+
+// GraphFormatForFile derives the graph format from the extension of the given file name
+func GraphFormatForFile(fileName string) (format GraphFormat, err error) {
+	var ok bool
+	if format, ok = graphFormatsByExtension[strings.ToLower(filepath.Ext(fileName))]; !ok {
+		err = fmt.Errorf("cannot derive graph format from %s, use one of .dot, .gv, .mmd or .mermaid", fileName)
+	}
+	return
+}
+
+// WriteGraphFile writes the search tree to fileName, the format is derived from the file extension
+func WriteGraphFile(fileName string, nodes []crawling.SearchTreeNode) (err error) {
+	var format GraphFormat
+	if format, err = GraphFormatForFile(fileName); err != nil {
+		return
+	}
+
+	var file *os.File
+	if file, err = os.Create(fileName); err != nil {
+		return
+	}
+
+	writer := bufio.NewWriter(file)
+	if err = WriteGraph(writer, format, nodes); err == nil {
+		err = writer.Flush()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return
+}
+
+// WriteGraph writes the search tree in the given format, the resolved path is highlighted
+func WriteGraph(w io.Writer, format GraphFormat, nodes []crawling.SearchTreeNode) error {
+	switch format {
+	case GraphFormatDOT:
+		return writeDOT(w, nodes)
+	case GraphFormatMermaid:
+		return writeMermaid(w, nodes)
+	default:
+		return fmt.Errorf("unknown graph format %s", format)
+	}
+}
+
+func writeDOT(w io.Writer, nodes []crawling.SearchTreeNode) error {
+	ew := &errWriter{w: w}
+	ew.printf("digraph search_tree {\n")
+	ew.printf("  rankdir=LR;\n")
+	ew.printf("  node [shape=box, style=rounded];\n")
+
+	for _, node := range nodes {
+		attributes := fmt.Sprintf(`label="%s", URL="%s"`, dotEscaper.Replace(node.Title), dotEscaper.Replace(node.URI))
+		if node.OnPath {
+			attributes += `, color=red, penwidth=2`
+		}
+		ew.printf("  n%d [%s];\n", node.ID, attributes)
+	}
+
+	for _, node := range nodes {
+		if !node.HasParent {
+			continue
+		}
+		var attributes []string
+		if node.OnPath {
+			attributes = append(attributes, "color=red", "penwidth=2")
+		}
+		if node.Interlanguage {
+			attributes = append(attributes, "style=dashed")
+		}
+		if len(attributes) > 0 {
+			ew.printf("  n%d -> n%d [%s];\n", node.ParentID, node.ID, strings.Join(attributes, ", "))
+		} else {
+			ew.printf("  n%d -> n%d;\n", node.ParentID, node.ID)
+		}
+	}
+
+	ew.printf("}\n")
+	return ew.err
+}
+
+func writeMermaid(w io.Writer, nodes []crawling.SearchTreeNode) error {
+	ew := &errWriter{w: w}
+	ew.printf("graph LR\n")
+
+	for _, node := range nodes {
+		ew.printf("  n%d[\"%s\"]\n", node.ID, mermaidEscaper.Replace(node.Title))
+	}
+
+	var pathNodes []string
+	var pathEdges []string
+	edgeIdx := 0
+	for _, node := range nodes {
+		if node.OnPath {
+			pathNodes = append(pathNodes, fmt.Sprintf("n%d", node.ID))
+		}
+		if !node.HasParent {
+			continue
+		}
+		if node.Interlanguage {
+			ew.printf("  n%d -.-> n%d\n", node.ParentID, node.ID)
+		} else {
+			ew.printf("  n%d --> n%d\n", node.ParentID, node.ID)
+		}
+		if node.OnPath {
+			pathEdges = append(pathEdges, fmt.Sprint(edgeIdx))
+		}
+		edgeIdx++
+	}
+
+	if len(pathNodes) > 0 {
+		ew.printf("  classDef path stroke:#f00,stroke-width:2px\n")
+		ew.printf("  class %s path\n", strings.Join(pathNodes, ","))
+	}
+
+	if len(pathEdges) > 0 {
+		ew.printf("  linkStyle %s stroke:#f00,stroke-width:2px\n", strings.Join(pathEdges, ","))
+	}
+
+	return ew.err
+}
+
+// errWriter remembers the first write error so that the graph writers do not have to check every single line
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
@@ -0,0 +1,201 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	"testing"
+)
+
+var (
+	searchTree = []crawling.SearchTreeNode{
+		{
+			ID:          0,
+			Title:       "Times New Roman",
+			URI:         "https://en.wikipedia.org/wiki/Times_New_Roman",
+			Descendants: 2,
+			OnPath:      true,
+		},
+		{
+			ID:        1,
+			ParentID:  0,
+			HasParent: true,
+			Title:     `"Serif"`,
+			URI:       "https://en.wikipedia.org/wiki/Serif",
+			Depth:     1,
+		},
+		{
+			ID:        2,
+			ParentID:  0,
+			HasParent: true,
+			Title:     "The Times",
+			URI:       "https://en.wikipedia.org/wiki/The_Times",
+			Depth:     1,
+			OnPath:    true,
+		},
+		{
+			ID:            3,
+			ParentID:      1,
+			HasParent:     true,
+			Title:         "de:Serife",
+			URI:           "https://de.wikipedia.org/wiki/Serife",
+			Depth:         2,
+			Interlanguage: true,
+		},
+	}
+)
+
+func TestParseGraphFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		wantFormat GraphFormat
+		wantErr    bool
+	}{
+		{
+			name:       "parse dot",
+			value:      "dot",
+			wantFormat: GraphFormatDOT,
+		},
+		{
+			name:       "parse format case insensitive",
+			value:      "Mermaid",
+			wantFormat: GraphFormatMermaid,
+		},
+		{
+			name:    "parse unknown format",
+			value:   "png",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFormat, err := ParseGraphFormat(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseGraphFormat() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && gotFormat != tt.wantFormat {
+				t.Errorf("ParseGraphFormat() = %v, want %v", gotFormat, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestGraphFormatForFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		fileName   string
+		wantFormat GraphFormat
+		wantErr    bool
+	}{
+		{
+			name:       "DOT file",
+			fileName:   "tree.dot",
+			wantFormat: GraphFormatDOT,
+		},
+		{
+			name:       "Graphviz file",
+			fileName:   "/tmp/tree.GV",
+			wantFormat: GraphFormatDOT,
+		},
+		{
+			name:       "Mermaid file",
+			fileName:   "tree.mmd",
+			wantFormat: GraphFormatMermaid,
+		},
+		{
+			name:     "unknown extension",
+			fileName: "tree.png",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFormat, err := GraphFormatForFile(tt.fileName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GraphFormatForFile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotFormat != tt.wantFormat {
+				t.Errorf("GraphFormatForFile() = %v, want %v", gotFormat, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestWriteGraph(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  GraphFormat
+		nodes   []crawling.SearchTreeNode
+		wantOut string
+		wantErr bool
+	}{
+		{
+			name:   "write DOT",
+			format: GraphFormatDOT,
+			nodes:  searchTree,
+			wantOut: `digraph search_tree {
+  rankdir=LR;
+  node [shape=box, style=rounded];
+  n0 [label="Times New Roman", URL="https://en.wikipedia.org/wiki/Times_New_Roman", color=red, penwidth=2];
+  n1 [label="\"Serif\"", URL="https://en.wikipedia.org/wiki/Serif"];
+  n2 [label="The Times", URL="https://en.wikipedia.org/wiki/The_Times", color=red, penwidth=2];
+  n3 [label="de:Serife", URL="https://de.wikipedia.org/wiki/Serife"];
+  n0 -> n1;
+  n0 -> n2 [color=red, penwidth=2];
+  n1 -> n3 [style=dashed];
+}
+`,
+		},
+		{
+			name:   "write Mermaid",
+			format: GraphFormatMermaid,
+			nodes:  searchTree,
+			wantOut: `graph LR
+  n0["Times New Roman"]
+  n1["#quot;Serif#quot;"]
+  n2["The Times"]
+  n3["de:Serife"]
+  n0 --> n1
+  n0 --> n2
+  n1 -.-> n3
+  classDef path stroke:#f00,stroke-width:2px
+  class n0,n2 path
+  linkStyle 1 stroke:#f00,stroke-width:2px
+`,
+		},
+		{
+			name:    "write unknown format",
+			format:  GraphFormat("png"),
+			nodes:   searchTree,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if err := WriteGraph(out, tt.format, tt.nodes); (err != nil) != tt.wantErr {
+				t.Errorf("WriteGraph() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotOut := out.String(); !tt.wantErr && gotOut != tt.wantOut {
+				t.Errorf("WriteGraph() = %v, want %v", gotOut, tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jobs
+
+import (
+	"github.com/baez90/shortest-path/internal/app/output"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	"time"
+)
+
+const (
+	// progressEventInterval is the minimum time between two page_processed events of a job
+	progressEventInterval = 500 * time.Millisecond
+	subscriberBufferSize  = 64
+)
+
+// Event is a step of the search of a running job as streamed to subscribers
+type Event struct {
+	Type             crawling.SearchEventKind `json:"type"`
+	Depth            uint16                   `json:"depth"`
+	FrontierSize     uint64                   `json:"frontier_size"`
+	ProcessedPages   uint64                   `json:"processed_pages"`
+	NextFrontierSize uint64                   `json:"next_frontier_size"`
+	FetchedPages     uint                     `json:"fetched_pages"`
+	DiscoveredPages  int                      `json:"discovered_pages"`
+	ElapsedMillis    int64                    `json:"elapsed_ms"`
+	PagesPerSecond   float64                  `json:"pages_per_second"`
+	// Path is the resolved path of path_found events
+	Path []output.Page `json:"path,omitempty"`
+}
+
+func newEvent(searchEvent crawling.SearchEvent) Event {
+	event := Event{
+		Type:             searchEvent.Kind,
+		Depth:            searchEvent.Progress.Depth,
+		FrontierSize:     searchEvent.Progress.FrontierSize,
+		ProcessedPages:   searchEvent.Progress.ProcessedPages,
+		NextFrontierSize: searchEvent.Progress.NextFrontierSize,
+		FetchedPages:     searchEvent.Progress.FetchedPages,
+		DiscoveredPages:  searchEvent.Progress.DiscoveredPages,
+		ElapsedMillis:    searchEvent.Elapsed.Milliseconds(),
+		PagesPerSecond:   searchEvent.PagesPerSecond,
+	}
+	if searchEvent.Path != nil {
+		event.Path = output.NewPath(searchEvent.Path)
+	}
+	return event
+}
+
+// Subscribe streams the events of the job until it is finished or unsubscribe is called.
+// Events are dropped if the subscriber does not keep up, the channel of finished jobs is closed right away.
+func (manager *Manager) Subscribe(id string) (events <-chan Event, unsubscribe func(), err error) {
+	subscription := make(chan Event, subscriberBufferSize)
+
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	active, ok := manager.active[id]
+	if !ok {
+		if _, err = manager.store.Get(id); err != nil {
+			return
+		}
+		close(subscription)
+		return subscription, func() {}, nil
+	}
+
+	active.subscribers[subscription] = true
+	unsubscribe = func() {
+		manager.lock.Lock()
+		defer manager.lock.Unlock()
+		if active.subscribers[subscription] {
+			delete(active.subscribers, subscription)
+			close(subscription)
+		}
+	}
+	return subscription, unsubscribe, nil
+}
+
+// jobObserver publishes the events of the search of a job
+type jobObserver struct {
+	crawling.NopObserver
+	manager *Manager
+	active  *activeJob
+}
+
+func (observer jobObserver) OnLevelStart(event crawling.SearchEvent) {
+	observer.manager.publish(observer.active, event)
+}
+
+func (observer jobObserver) OnLevelFinished(event crawling.SearchEvent) {
+	observer.manager.publish(observer.active, event)
+}
+
+func (observer jobObserver) OnPageProcessed(event crawling.SearchEvent) {
+	observer.manager.publish(observer.active, event)
+}
+
+func (observer jobObserver) OnPathFound(event crawling.SearchEvent) {
+	observer.manager.publish(observer.active, event)
+}
+
+// publish passes the search event to all subscribers of the job, page_processed events are throttled
+func (manager *Manager) publish(active *activeJob, searchEvent crawling.SearchEvent) {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	if searchEvent.Kind == crawling.PageProcessed {
+		if time.Since(active.lastProgressEvent) < progressEventInterval {
+			return
+		}
+		active.lastProgressEvent = time.Now()
+	}
+
+	if len(active.subscribers) == 0 {
+		return
+	}
+
+	event := newEvent(searchEvent)
+	for subscription := range active.subscribers {
+		select {
+		case subscription <- event:
+		default:
+		}
+	}
+}
+
+// closeSubscriptions ends the streams of a finished job, the lock has to be held
+func (active *activeJob) closeSubscriptions() {
+	for subscription := range active.subscribers {
+		delete(active.subscribers, subscription)
+		close(subscription)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jobs
+
+import (
+	"errors"
+	"github.com/baez90/shortest-path/internal/app/output"
+	"time"
+)
+
+const (
+	StateQueued    State = "queued"
+	StateRunning   State = "running"
+	StateDone      State = "done"
+	StateFailed    State = "failed"
+	StateCancelled State = "cancelled"
+)
+
+var (
+	// ErrJobNotFound is returned for IDs of jobs which were never submitted or already deleted
+	ErrJobNotFound = errors.New("job not found")
+	// ErrQueueFull is returned if no further job can be queued until a queued job is started
+	ErrQueueFull = errors.New("job queue is full")
+)
+
+// State is the lifecycle state of a job
+type State string
+
+// Finished checks whether the job will not change anymore
+func (state State) Finished() bool {
+	return state == StateDone || state == StateFailed || state == StateCancelled
+}
+
+// Job is a search running in the background.
+// A job is done if the search completed, even if the target was not reached within max hops.
+type Job struct {
+	ID     string `json:"id"`
+	State  State  `json:"state"`
+	Start  string `json:"start"`
+	Target string `json:"target"`
+	// Depth is the BFS level currently processed or - for finished jobs - the last processed level
+	Depth           uint16               `json:"depth"`
+	FetchedPages    uint                 `json:"fetched_pages"`
+	DiscoveredPages int                  `json:"discovered_pages"`
+	Error           string               `json:"error,omitempty"`
+	Result          *output.SearchResult `json:"result,omitempty"`
+	SubmittedAt     time.Time            `json:"submitted_at"`
+	StartedAt       *time.Time           `json:"started_at,omitempty"`
+	FinishedAt      *time.Time           `json:"finished_at,omitempty"`
+}
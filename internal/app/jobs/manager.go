@@ -0,0 +1,284 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/baez90/shortest-path/internal/app/output"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	log "github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+const (
+	interruptedJobError = "the server stopped before the job finished"
+)
+
+// Manager runs submitted searches in the background with a fixed number of workers.
+// Queued and running jobs are kept in memory, every state change is persisted to the store.
+type Manager struct {
+	store   *Store
+	queue   chan *activeJob
+	lock    sync.Mutex
+	active  map[string]*activeJob
+	closing bool
+	workers sync.WaitGroup
+}
+
+// activeJob is a queued or running job with the crawler running its search
+type activeJob struct {
+	job       Job
+	crawler   *crawling.WikiCrawler
+	ctx       context.Context
+	cancel    context.CancelFunc
+	cancelled bool
+	// subscribers receive the events of the search until the job is finished
+	subscribers       map[chan Event]bool
+	lastProgressEvent time.Time
+}
+
+// NewManager starts the workers processing the jobs.
+// Jobs left queued or running by a previous process are marked as failed as their searches are lost.
+func NewManager(store *Store, workers, queueSize int) (manager *Manager, err error) {
+	if err = failInterruptedJobs(store); err != nil {
+		return
+	}
+
+	manager = &Manager{
+		store:  store,
+		queue:  make(chan *activeJob, queueSize),
+		active: make(map[string]*activeJob),
+	}
+	for i := 0; i < workers; i++ {
+		manager.workers.Add(1)
+		go manager.work()
+	}
+	return
+}
+
+func failInterruptedJobs(store *Store) error {
+	jobs, err := store.Jobs()
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if job.State.Finished() {
+			continue
+		}
+		finishedAt := time.Now()
+		job.State = StateFailed
+		job.Error = interruptedJobError
+		job.FinishedAt = &finishedAt
+		if err = store.Put(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CrawlerFactory creates the crawler of a job, the given options let the manager follow the search
+type CrawlerFactory func(options ...crawling.Option) (*crawling.WikiCrawler, error)
+
+// Submit queues the search of the crawler created by newCrawler, errors of newCrawler are returned as they are
+func (manager *Manager) Submit(newCrawler CrawlerFactory) (job Job, err error) {
+	var id string
+	if id, err = newJobID(); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	active := &activeJob{
+		ctx:         ctx,
+		cancel:      cancel,
+		subscribers: make(map[chan Event]bool),
+	}
+	crawler, err := newCrawler(crawling.WithObserver(jobObserver{manager: manager, active: active}))
+	if err != nil {
+		cancel()
+		return
+	}
+	active.crawler = crawler
+	active.job = Job{
+		ID:          id,
+		State:       StateQueued,
+		Start:       crawler.StartPage(),
+		Target:      crawler.TargetPage(),
+		SubmittedAt: time.Now(),
+	}
+
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	if manager.closing {
+		cancel()
+		return job, ErrQueueFull
+	}
+
+	if err = manager.store.Put(active.job); err != nil {
+		cancel()
+		return
+	}
+
+	select {
+	case manager.queue <- active:
+	default:
+		cancel()
+		if deleteErr := manager.store.Delete(id); deleteErr != nil {
+			log.WithError(deleteErr).Warnf("Failed to delete rejected job %s", id)
+		}
+		return job, ErrQueueFull
+	}
+
+	manager.active[id] = active
+	return active.job, nil
+}
+
+// Get returns the current state of the job, the counters of running jobs reflect the progress of their search
+func (manager *Manager) Get(id string) (Job, error) {
+	manager.lock.Lock()
+	if active, ok := manager.active[id]; ok {
+		job := active.snapshot()
+		manager.lock.Unlock()
+		return job, nil
+	}
+	manager.lock.Unlock()
+
+	return manager.store.Get(id)
+}
+
+// Cancel stops a queued or running job, the job is kept as cancelled.
+// Finished jobs are deleted from the store instead, the returned job is their last state.
+func (manager *Manager) Cancel(id string) (job Job, err error) {
+	manager.lock.Lock()
+	if active, ok := manager.active[id]; ok {
+		active.cancelled = true
+		active.cancel()
+		job = active.snapshot()
+		// a queued job is finished by the worker dequeuing it which might take a while
+		manager.persist(job)
+		manager.lock.Unlock()
+		return
+	}
+	manager.lock.Unlock()
+
+	if job, err = manager.store.Get(id); err != nil {
+		return
+	}
+	err = manager.store.Delete(id)
+	return
+}
+
+// Close cancels all queued and running jobs and waits for the workers to finish.
+// The jobs are persisted as failed as they were not cancelled by a client.
+func (manager *Manager) Close() {
+	manager.lock.Lock()
+	manager.closing = true
+	for _, active := range manager.active {
+		active.cancel()
+	}
+	close(manager.queue)
+	manager.lock.Unlock()
+
+	manager.workers.Wait()
+}
+
+func (manager *Manager) work() {
+	defer manager.workers.Done()
+	for active := range manager.queue {
+		manager.run(active)
+	}
+}
+
+func (manager *Manager) run(active *activeJob) {
+	var result *output.SearchResult
+	var err error
+	if active.ctx.Err() == nil {
+		manager.update(active, func(job *Job) {
+			startedAt := time.Now()
+			job.State = StateRunning
+			job.StartedAt = &startedAt
+		})
+
+		start := time.Now()
+		var res crawling.TraversalResult
+		res, err = active.crawler.SearchShortestPath(active.ctx)
+		searchResult := output.NewSearchResult(active.crawler, res, err, time.Since(start))
+		result = &searchResult
+	}
+	defer active.cancel()
+
+	manager.update(active, func(job *Job) {
+		delete(manager.active, job.ID)
+		active.closeSubscriptions()
+		finishedAt := time.Now()
+		job.FinishedAt = &finishedAt
+		job.Result = result
+		switch {
+		case result != nil && (err == nil || err == crawling.ErrMaxHopsReached):
+			job.State = StateDone
+		case active.cancelled:
+			job.State = StateCancelled
+		case manager.closing:
+			job.State = StateFailed
+			job.Error = interruptedJobError
+		default:
+			job.State = StateFailed
+			job.Error = result.Error
+		}
+	})
+}
+
+// update modifies the job and persists the change
+func (manager *Manager) update(active *activeJob, modify func(job *Job)) {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+	modify(&active.job)
+	manager.persist(active.snapshot())
+}
+
+// persist stores the job, the lock has to be held to keep the stored states in order
+func (manager *Manager) persist(job Job) {
+	if err := manager.store.Put(job); err != nil {
+		log.
+			WithError(err).
+			Errorf("Failed to persist job %s", job.ID)
+	}
+}
+
+// snapshot returns the job with the counters of its search
+func (active *activeJob) snapshot() Job {
+	job := active.job
+	if job.State == StateRunning || job.Result != nil {
+		progress := active.crawler.Progress()
+		job.Depth = progress.Depth
+		job.FetchedPages = progress.FetchedPages
+		job.DiscoveredPages = progress.DiscoveredPages
+	}
+	if active.cancelled && !job.State.Finished() {
+		job.State = StateCancelled
+	}
+	return job
+}
+
+func newJobID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
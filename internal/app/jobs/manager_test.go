@@ -0,0 +1,219 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jobs
+
+import (
+	"github.com/baez90/shortest-path/pkg/crawling"
+	"github.com/baez90/shortest-path/pkg/crawling/crawlingtest"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestWiki links page n to page n+1, the page Blocking never responds until the search is cancelled
+func newTestWiki() *crawlingtest.Wiki {
+	wiki := crawlingtest.NewWiki(crawlingtest.Chain)
+	wiki.Blocking = "Blocking"
+	return wiki
+}
+
+func testSearch(start, target string, maxHops uint16) CrawlerFactory {
+	return func(options ...crawling.Option) (*crawling.WikiCrawler, error) {
+		return crawling.NewWikiCrawler(crawlingtest.PageURI(start), crawlingtest.PageURI(target), append([]crawling.Option{
+			crawling.WithMaxHops(maxHops),
+			crawling.WithPageCache(crawling.NewPageCache(newTestWiki().Fetch, 1<<20)),
+		}, options...)...)
+	}
+}
+
+func openTestStore(t *testing.T) (store *Store, cleanup func()) {
+	dir, err := ioutil.TempDir("", "shortest-path-jobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store, err = OpenStore(filepath.Join(dir, "jobs.db")); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return store, func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// awaitJob polls the job until its worker finished it
+func awaitJob(t *testing.T, manager *Manager, id string) Job {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := manager.Get(id)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if job.FinishedAt != nil {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return Job{}
+}
+
+func TestManager_Submit(t *testing.T) {
+	tests := []struct {
+		name      string
+		search    CrawlerFactory
+		wantState State
+		wantFound bool
+		wantDepth uint16
+	}{
+		{
+			name:      "find path",
+			search:    testSearch("Page_0", "Page_3", 5),
+			wantState: StateDone,
+			wantFound: true,
+			wantDepth: 2,
+		},
+		{
+			name:      "complete search without reaching the target",
+			search:    testSearch("Page_0", "Page_9", 2),
+			wantState: StateDone,
+			wantDepth: 2,
+		},
+		{
+			name:      "skip unavailable pages",
+			search:    testSearch("Invalid", "Page_9", 2),
+			wantState: StateDone,
+			wantDepth: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, cleanup := openTestStore(t)
+			defer cleanup()
+			manager, err := NewManager(store, 1, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer manager.Close()
+
+			submitted, err := manager.Submit(tt.search)
+			if err != nil {
+				t.Fatalf("Submit() error = %v", err)
+			}
+			if submitted.State != StateQueued {
+				t.Errorf("Submit() state = %s, want %s", submitted.State, StateQueued)
+			}
+
+			job := awaitJob(t, manager, submitted.ID)
+			if job.State != tt.wantState || job.Depth != tt.wantDepth {
+				t.Errorf("job state = %s at depth %d, want %s at depth %d", job.State, job.Depth, tt.wantState, tt.wantDepth)
+			}
+			if job.Result == nil || job.Result.Found != tt.wantFound {
+				t.Fatalf("job result = %+v, want found %v", job.Result, tt.wantFound)
+			}
+
+			stored, err := store.Get(submitted.ID)
+			if err != nil || stored.State != job.State {
+				t.Errorf("stored job = %+v, error = %v", stored, err)
+			}
+		})
+	}
+}
+
+func TestManager_Cancel(t *testing.T) {
+	store, cleanup := openTestStore(t)
+	defer cleanup()
+	manager, err := NewManager(store, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Close()
+
+	submitted, err := manager.Submit(testSearch("Blocking", "Page_3", 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = manager.Cancel(submitted.ID); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	if job := awaitJob(t, manager, submitted.ID); job.State != StateCancelled {
+		t.Errorf("job state = %s, want %s", job.State, StateCancelled)
+	}
+
+	// cancelling a finished job deletes it
+	if _, err = manager.Cancel(submitted.ID); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	if _, err = manager.Get(submitted.ID); err != ErrJobNotFound {
+		t.Errorf("Get() error = %v, want %v", err, ErrJobNotFound)
+	}
+}
+
+func TestManager_QueueFull(t *testing.T) {
+	store, cleanup := openTestStore(t)
+	defer cleanup()
+	manager, err := NewManager(store, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Close()
+
+	running, err := manager.Submit(testSearch("Blocking", "Page_3", 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// wait for the worker to dequeue the blocking job
+	for job, _ := manager.Get(running.ID); job.State == StateQueued; job, _ = manager.Get(running.ID) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if _, err = manager.Submit(testSearch("Page_0", "Page_3", 5)); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if _, err = manager.Submit(testSearch("Page_0", "Page_3", 5)); err != ErrQueueFull {
+		t.Errorf("Submit() error = %v, want %v", err, ErrQueueFull)
+	}
+}
+
+func TestNewManager_FailInterruptedJobs(t *testing.T) {
+	store, cleanup := openTestStore(t)
+	defer cleanup()
+
+	if err := store.Put(Job{ID: "running", State: StateRunning}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(Job{ID: "done", State: StateDone}); err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := NewManager(store, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Close()
+
+	for id, wantState := range map[string]State{"running": StateFailed, "done": StateDone} {
+		job, err := manager.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.State != wantState {
+			t.Errorf("job %s state = %s, want %s", id, job.State, wantState)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	bolt "go.etcd.io/bbolt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var (
+	jobsBucket = []byte("jobs")
+)
+
+// Store persists jobs in a BoltDB file so that their results survive restarts
+type Store struct {
+	db *bolt.DB
+}
+
+// OpenStore opens or creates the store at the given path, missing directories are created
+func OpenStore(path string) (store *Store, err error) {
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+
+	var db *bolt.DB
+	if db, err = bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second}); err != nil {
+		return nil, fmt.Errorf("failed to open job store %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, bucketErr := tx.CreateBucketIfNotExists(jobsBucket)
+		return bucketErr
+	})
+	if err != nil {
+		db.Close()
+		return
+	}
+	return &Store{db: db}, nil
+}
+
+// Put creates or replaces the job
+func (store *Store) Put(job Job) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return store.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Put([]byte(job.ID), value)
+	})
+}
+
+// Get returns the job with the given ID or ErrJobNotFound
+func (store *Store) Get(id string) (job Job, err error) {
+	err = store.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(jobsBucket).Get([]byte(id))
+		if value == nil {
+			return ErrJobNotFound
+		}
+		return json.Unmarshal(value, &job)
+	})
+	return
+}
+
+// Delete removes the job, deleting an unknown job is no error
+func (store *Store) Delete(id string) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Delete([]byte(id))
+	})
+}
+
+// Jobs returns all stored jobs ordered by their ID
+func (store *Store) Jobs() (jobs []Job, err error) {
+	err = store.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(_, value []byte) error {
+			var job Job
+			if unmarshalErr := json.Unmarshal(value, &job); unmarshalErr != nil {
+				return unmarshalErr
+			}
+			jobs = append(jobs, job)
+			return nil
+		})
+	})
+	return
+}
+
+func (store *Store) Close() error {
+	return store.db.Close()
+}
@@ -0,0 +1,270 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics collects Prometheus metrics of page retrievals and searches.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	namespace = "shortest_path"
+
+	outcomeFound     = "found"
+	outcomeNotFound  = "not_found"
+	outcomeCancelled = "cancelled"
+	outcomeFailed    = "failed"
+)
+
+// Metrics observes searches and page retrievals, it is safe for concurrent use by multiple crawlers.
+// All metrics are registered in a registry of their own exposed by Handler or WriteTextfile.
+type Metrics struct {
+	crawling.NopObserver
+	registry        *prometheus.Registry
+	client          *http.Client
+	fetchDuration   *prometheus.HistogramVec
+	downloadedBytes prometheus.Counter
+	fetchedPages    prometheus.Counter
+	cachedPages     prometheus.Counter
+	fetchErrors     prometheus.Counter
+	parseErrors     prometheus.Counter
+	discoveredLinks prometheus.Histogram
+	searches        *prometheus.CounterVec
+	searchDuration  prometheus.Histogram
+	searchHops      prometheus.Histogram
+}
+
+// New creates and registers all metrics of page retrievals and searches
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		fetchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "fetch_duration_seconds",
+			Help:      "Time until the response headers of a page retrieval arrived by HTTP status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"code"}),
+		downloadedBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "downloaded_bytes_total",
+			Help:      "Bytes of response bodies read from the wiki.",
+		}),
+		fetchedPages: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "fetched_pages_total",
+			Help:      "Pages retrieved and parsed by searches, including pages answered by the page cache.",
+		}),
+		cachedPages: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cached_pages_total",
+			Help:      "Pages processed by searches with the links answered by the link cache.",
+		}),
+		fetchErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "fetch_errors_total",
+			Help:      "Pages which could not be retrieved.",
+		}),
+		parseErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "parse_errors_total",
+			Help:      "Pages which were retrieved but could not be parsed.",
+		}),
+		discoveredLinks: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "discovered_links_per_page",
+			Help:      "Links extracted from a page leading to pages not discovered before.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+		}),
+		searches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "searches_total",
+			Help:      "Finished searches by outcome: found, not_found, cancelled or failed.",
+		}, []string{"outcome"}),
+		searchDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "search_duration_seconds",
+			Help:      "Duration of finished searches since they were started or resumed.",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 14),
+		}),
+		searchHops: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "search_hops",
+			Help:      "Length of the paths found by searches.",
+			Buckets:   prometheus.LinearBuckets(1, 1, 10),
+		}),
+	}
+	m.client = &http.Client{Transport: m.InstrumentTransport(http.DefaultTransport)}
+
+	m.registry.MustRegister(
+		m.fetchDuration,
+		m.downloadedBytes,
+		m.fetchedPages,
+		m.cachedPages,
+		m.fetchErrors,
+		m.parseErrors,
+		m.discoveredLinks,
+		m.searches,
+		m.searchDuration,
+		m.searchHops,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format e.g. on /metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// WriteTextfile writes the metrics to the given file to be collected by the textfile collector of the node exporter.
+// The file is replaced atomically.
+func (m *Metrics) WriteTextfile(path string) error {
+	return prometheus.WriteToTextfile(path, m.registry)
+}
+
+// HTTPClient returns a client retrieving pages via the instrumented default transport
+func (m *Metrics) HTTPClient() *http.Client {
+	return m.client
+}
+
+// CrawlerOptions let a crawler retrieve pages via the instrumented client and observe its searches
+func (m *Metrics) CrawlerOptions() []crawling.Option {
+	return []crawling.Option{
+		crawling.WithHTTPClient(m.client),
+		crawling.WithObserver(m),
+	}
+}
+
+// RegisterPageCache exposes the hits and misses of the cache shared by all searches
+func (m *Metrics) RegisterPageCache(cache *crawling.PageCache) {
+	m.registerCache("page_cache", "Page retrievals", cache.Stats)
+}
+
+// RegisterLinkCache exposes the hits and misses of the link cache shared by all searches of an engine
+func (m *Metrics) RegisterLinkCache(cache *crawling.LinkCache) {
+	m.registerCache("link_cache", "Processed pages", cache.Stats)
+}
+
+// registerCache exposes the hits, the misses and the hit ratio of a cache, subject describes what the cache answers
+func (m *Metrics) registerCache(name, subject string, stats func() (hits, misses uint64)) {
+	cacheName := strings.Replace(name, "_", " ", -1)
+	m.registry.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      name + "_hits_total",
+			Help:      fmt.Sprintf("%s answered by the %s.", subject, cacheName),
+		}, func() float64 {
+			hits, _ := stats()
+			return float64(hits)
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      name + "_misses_total",
+			Help:      fmt.Sprintf("%s missing in the %s.", subject, cacheName),
+		}, func() float64 {
+			_, misses := stats()
+			return float64(misses)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      name + "_hit_ratio",
+			Help:      fmt.Sprintf("Ratio of %s answered by the %s, 0 before the first lookup.", strings.ToLower(subject), cacheName),
+		}, func() float64 {
+			hits, misses := stats()
+			if hits+misses == 0 {
+				return 0
+			}
+			return float64(hits) / float64(hits+misses)
+		}),
+	)
+}
+
+// InstrumentTransport measures the latency and the downloaded bytes of every retrieval via next.
+// Retrievals failing without response are counted as fetch errors by the observer.
+func (m *Metrics) InstrumentTransport(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+		m.fetchDuration.WithLabelValues(strconv.Itoa(resp.StatusCode)).Observe(time.Since(start).Seconds())
+		resp.Body = &countingBody{ReadCloser: resp.Body, counter: m.downloadedBytes}
+		return resp, nil
+	})
+}
+
+func (m *Metrics) OnPageFetched(string, time.Duration) {
+	m.fetchedPages.Inc()
+}
+
+func (m *Metrics) OnLinksCached(string) {
+	m.cachedPages.Inc()
+}
+
+func (m *Metrics) OnLinksDiscovered(_ string, links []string) {
+	m.discoveredLinks.Observe(float64(len(links)))
+}
+
+func (m *Metrics) OnFetchError(_ string, err error) {
+	var parseErr *crawling.ParseError
+	if errors.As(err, &parseErr) {
+		m.parseErrors.Inc()
+		return
+	}
+	m.fetchErrors.Inc()
+}
+
+func (m *Metrics) OnSearchFinished(hops int, duration time.Duration, err error) {
+	outcome := outcomeFailed
+	switch {
+	case err == nil:
+		outcome = outcomeFound
+		m.searchHops.Observe(float64(hops))
+	case err == crawling.ErrMaxHopsReached:
+		outcome = outcomeNotFound
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		outcome = outcomeCancelled
+	}
+	m.searches.WithLabelValues(outcome).Inc()
+	m.searchDuration.Observe(duration.Seconds())
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// countingBody adds the bytes read from the response body to the counter
+type countingBody struct {
+	io.ReadCloser
+	counter prometheus.Counter
+}
+
+func (body *countingBody) Read(p []byte) (n int, err error) {
+	n, err = body.ReadCloser.Read(p)
+	body.counter.Add(float64(n))
+	return
+}
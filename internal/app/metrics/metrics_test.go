@@ -0,0 +1,160 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics_InstrumentTransport(t *testing.T) {
+	wiki := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/wiki/Missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, "<html></html>")
+	}))
+	defer wiki.Close()
+
+	m := New()
+	fetch := crawling.NewPageFetcher(m.HTTPClient())
+	body, err := fetch(context.Background(), wiki.URL+"/wiki/Go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = ioutil.ReadAll(body)
+	_ = body.Close()
+	if _, err = fetch(context.Background(), wiki.URL+"/wiki/Missing"); err == nil {
+		t.Fatal("expected error for missing page")
+	}
+
+	if got := testutil.ToFloat64(m.downloadedBytes); got != float64(len("<html></html>")) {
+		t.Errorf("downloaded bytes = %v, want %d", got, len("<html></html>"))
+	}
+	families, err := m.registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, code := range []string{"200", "404"} {
+		if !hasLabel(families, "shortest_path_fetch_duration_seconds", "code", code) {
+			t.Errorf("no fetch duration with status code %s", code)
+		}
+	}
+}
+
+// hasLabel checks whether any metric of the family has the given label value
+func hasLabel(families []*dto.MetricFamily, family, label, value string) bool {
+	for _, metricFamily := range families {
+		if metricFamily.GetName() != family {
+			continue
+		}
+		for _, metric := range metricFamily.GetMetric() {
+			for _, pair := range metric.GetLabel() {
+				if pair.GetName() == label && pair.GetValue() == value {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func TestMetrics_Observer(t *testing.T) {
+	m := New()
+	m.OnPageFetched("https://en.wikipedia.org/wiki/Go", time.Millisecond)
+	m.OnLinksDiscovered("https://en.wikipedia.org/wiki/Go", []string{"https://en.wikipedia.org/wiki/Rust"})
+	m.OnFetchError("https://en.wikipedia.org/wiki/C", errors.New("503 Service Unavailable"))
+	m.OnFetchError("https://en.wikipedia.org/wiki/D", &crawling.ParseError{PageURI: "https://en.wikipedia.org/wiki/D", Err: io.ErrUnexpectedEOF})
+	m.OnSearchFinished(3, time.Second, nil)
+	m.OnSearchFinished(0, time.Second, crawling.ErrMaxHopsReached)
+	m.OnSearchFinished(0, time.Second, context.Canceled)
+	m.OnSearchFinished(0, time.Second, errors.New("disk full"))
+
+	tests := []struct {
+		name  string
+		value float64
+		want  float64
+	}{
+		{name: "fetched pages", value: testutil.ToFloat64(m.fetchedPages), want: 1},
+		{name: "fetch errors", value: testutil.ToFloat64(m.fetchErrors), want: 1},
+		{name: "parse errors", value: testutil.ToFloat64(m.parseErrors), want: 1},
+		{name: "found searches", value: testutil.ToFloat64(m.searches.WithLabelValues(outcomeFound)), want: 1},
+		{name: "not found searches", value: testutil.ToFloat64(m.searches.WithLabelValues(outcomeNotFound)), want: 1},
+		{name: "cancelled searches", value: testutil.ToFloat64(m.searches.WithLabelValues(outcomeCancelled)), want: 1},
+		{name: "failed searches", value: testutil.ToFloat64(m.searches.WithLabelValues(outcomeFailed)), want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.value != tt.want {
+				t.Errorf("%s = %v, want %v", tt.name, tt.value, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetrics_WriteTextfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "shortest-path-metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	m := New()
+	cache := crawling.NewPageCache(func(context.Context, string) (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader("<html></html>")), nil
+	}, 1024)
+	m.RegisterPageCache(cache)
+	for i := 0; i < 4; i++ {
+		if _, err = cache.Fetch(context.Background(), "https://en.wikipedia.org/wiki/Go"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m.OnSearchFinished(2, 1500*time.Millisecond, nil)
+
+	path := filepath.Join(dir, "shortest-path.prom")
+	if err = m.WriteTextfile(path); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"shortest_path_page_cache_hits_total 3",
+		"shortest_path_page_cache_misses_total 1",
+		"shortest_path_page_cache_hit_ratio 0.75",
+		`shortest_path_searches_total{outcome="found"} 1`,
+		"shortest_path_search_duration_seconds_sum 1.5",
+		`shortest_path_search_hops_bucket{le="2"} 1`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("metrics file does not contain %q:\n%s", want, content)
+		}
+	}
+}
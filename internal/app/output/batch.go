@@ -0,0 +1,125 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// BatchFormat is the output format of batch searches writing one row per pair
+type BatchFormat string
+
+const (
+	BatchFormatCSV   BatchFormat = "csv"
+	BatchFormatJSONL BatchFormat = "jsonl"
+
+	// pathSeparator separates the titles of the path in CSV rows
+	pathSeparator = " > "
+)
+
+var (
+	batchFormats = []BatchFormat{BatchFormatCSV, BatchFormatJSONL}
+	// batchCSVHeader are the columns of the CSV format, the path is written as the titles of its pages
+	batchCSVHeader = []string{"pair", "start", "target", "found", "error", "hops", "path", "duration_ms", "fetched_pages", "discovered_pages"}
+)
+
+func ParseBatchFormat(value string) (format BatchFormat, err error) {
+	for _, format = range batchFormats {
+		if strings.EqualFold(string(format), value) {
+			return
+		}
+	}
+	err = fmt.Errorf("unknown batch output format %s, supported formats are %v", value, batchFormats)
+	return
+}
+
+// BatchResult is the result of a single pair of a batch, Pair is the position of the pair in the input
+type BatchResult struct {
+	Pair int `json:"pair"`
+	SearchResult
+}
+
+// BatchWriter writes the result of every pair as a single row as soon as it is available
+type BatchWriter struct {
+	format        BatchFormat
+	csv           *csv.Writer
+	json          *json.Encoder
+	headerWritten bool
+}
+
+func NewBatchWriter(w io.Writer, format BatchFormat) *BatchWriter {
+	return &BatchWriter{
+		format: format,
+		csv:    csv.NewWriter(w),
+		json:   json.NewEncoder(w),
+	}
+}
+
+// Write writes the row of the result
+func (writer *BatchWriter) Write(result BatchResult) error {
+	if writer.format == BatchFormatJSONL {
+		return writer.json.Encode(result)
+	}
+
+	if err := writer.writeHeader(); err != nil {
+		return err
+	}
+	titles := make([]string, 0, len(result.Path))
+	for _, page := range result.Path {
+		titles = append(titles, page.Title)
+	}
+	return writer.writeCSV([]string{
+		strconv.Itoa(result.Pair),
+		result.Start,
+		result.Target,
+		strconv.FormatBool(result.Found),
+		result.Error,
+		strconv.Itoa(result.Hops),
+		strings.Join(titles, pathSeparator),
+		strconv.FormatInt(result.DurationMillis, 10),
+		strconv.FormatUint(uint64(result.FetchedPages), 10),
+		strconv.Itoa(result.DiscoveredPages),
+	})
+}
+
+// Close writes the CSV header if no row was written at all
+func (writer *BatchWriter) Close() error {
+	if writer.format == BatchFormatJSONL {
+		return nil
+	}
+	return writer.writeHeader()
+}
+
+func (writer *BatchWriter) writeHeader() error {
+	if writer.headerWritten {
+		return nil
+	}
+	writer.headerWritten = true
+	return writer.writeCSV(batchCSVHeader)
+}
+
+// writeCSV writes and flushes the row so that every row is visible as soon as the pair is done
+func (writer *BatchWriter) writeCSV(row []string) error {
+	if err := writer.csv.Write(row); err != nil {
+		return err
+	}
+	writer.csv.Flush()
+	return writer.csv.Error()
+}
@@ -0,0 +1,83 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBatchWriter(t *testing.T) {
+	failedResult := SearchResult{
+		Start:          "Times New Roman",
+		Target:         "",
+		Error:          "pair requires start and target",
+		Path:           []Page{},
+		DurationMillis: 0,
+	}
+	tests := []struct {
+		name    string
+		format  BatchFormat
+		results []BatchResult
+		want    string
+	}{
+		{
+			name:   "CSV",
+			format: BatchFormatCSV,
+			results: []BatchResult{
+				{Pair: 1, SearchResult: foundResult},
+				{Pair: 2, SearchResult: failedResult},
+			},
+			want: `pair,start,target,found,error,hops,path,duration_ms,fetched_pages,discovered_pages
+1,https://en.wikipedia.org/wiki/Times_New_Roman,https://en.wikipedia.org/wiki/The_Times,true,,1,Times New Roman > The Times,42,1,334
+2,Times New Roman,,false,pair requires start and target,0,,0,0,0
+`,
+		},
+		{
+			name:   "CSV without results",
+			format: BatchFormatCSV,
+			want: `pair,start,target,found,error,hops,path,duration_ms,fetched_pages,discovered_pages
+`,
+		},
+		{
+			name:   "JSON lines",
+			format: BatchFormatJSONL,
+			results: []BatchResult{
+				{Pair: 1, SearchResult: foundResult},
+				{Pair: 2, SearchResult: failedResult},
+			},
+			want: `{"pair":1,"start":"https://en.wikipedia.org/wiki/Times_New_Roman","target":"https://en.wikipedia.org/wiki/The_Times","found":true,"path":[{"title":"Times New Roman","url":"https://en.wikipedia.org/wiki/Times_New_Roman"},{"title":"The Times","url":"https://en.wikipedia.org/wiki/The_Times"}],"hops":1,"duration_ms":42,"fetched_pages":1,"discovered_pages":334}
+{"pair":2,"start":"Times New Roman","target":"","found":false,"error":"pair requires start and target","path":[],"hops":0,"duration_ms":0,"fetched_pages":0,"discovered_pages":0}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := &bytes.Buffer{}
+			writer := NewBatchWriter(buffer, tt.format)
+			for _, result := range tt.results {
+				if err := writer.Write(result); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if buffer.String() != tt.want {
+				t.Errorf("BatchWriter wrote\n%s\nwant\n%s", buffer.String(), tt.want)
+			}
+		})
+	}
+}
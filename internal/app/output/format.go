@@ -0,0 +1,146 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+	FormatYAML Format = "yaml"
+)
+
+var (
+	formats = []Format{FormatText, FormatJSON, FormatCSV, FormatYAML}
+	// csvHeader are the columns of the CSV format, every page on the path is written as a separate row
+	csvHeader = []string{"position", "title", "url", "found", "error", "hops", "duration_ms", "fetched_pages", "discovered_pages", "anchor_text", "section", "snippet", "interlanguage"}
+)
+
+func ParseFormat(value string) (format Format, err error) {
+	for _, format = range formats {
+		if strings.EqualFold(string(format), value) {
+			return
+		}
+	}
+	err = fmt.Errorf("unknown output format %s, supported formats are %v", value, formats)
+	return
+}
+
+// Write writes the search result to w in the given format
+func Write(w io.Writer, format Format, result SearchResult) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case FormatYAML:
+		return yaml.NewEncoder(w).Encode(result)
+	case FormatCSV:
+		return writeCSV(w, result)
+	case FormatText:
+		return writeText(w, result)
+	default:
+		return fmt.Errorf("unknown output format %s", format)
+	}
+}
+
+func writeCSV(w io.Writer, result SearchResult) (err error) {
+	writer := csv.NewWriter(w)
+	if err = writer.Write(csvHeader); err != nil {
+		return
+	}
+
+	summary := []string{
+		strconv.FormatBool(result.Found),
+		result.Error,
+		strconv.Itoa(result.Hops),
+		strconv.FormatInt(result.DurationMillis, 10),
+		strconv.FormatUint(uint64(result.FetchedPages), 10),
+		strconv.Itoa(result.DiscoveredPages),
+	}
+
+	if len(result.Path) == 0 {
+		err = writer.Write(csvRow([]string{"", "", ""}, summary, nil, false))
+	}
+
+	for idx, page := range result.Path {
+		if err != nil {
+			break
+		}
+		err = writer.Write(csvRow([]string{strconv.Itoa(idx), page.Title, page.URL}, summary, page.Link, page.Interlanguage))
+	}
+
+	if err != nil {
+		return
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func csvRow(page, summary []string, link *Link, interlanguage bool) (row []string) {
+	row = append(append(row, page...), summary...)
+	if link == nil {
+		row = append(row, "", "", "")
+	} else {
+		row = append(row, link.AnchorText, link.Section, link.Snippet)
+	}
+	return append(row, strconv.FormatBool(interlanguage))
+}
+
+func writeText(w io.Writer, result SearchResult) (err error) {
+	lines := make([]string, 0, len(result.Path)+3)
+	if result.Found {
+		lines = append(lines, fmt.Sprintf("Resolved path with %d hops in %d ms", result.Hops, result.DurationMillis))
+	} else {
+		lines = append(lines, fmt.Sprintf("No path found from %s to %s after %d ms: %s", result.Start, result.Target, result.DurationMillis, result.Error))
+	}
+
+	for idx, page := range result.Path {
+		lines = append(lines, fmt.Sprintf("%d. %s (%s)", idx, page.Title, page.URL))
+		if page.Interlanguage {
+			lines = append(lines, "   reached via interlanguage link")
+		}
+		if link := page.Link; link != nil {
+			section := link.Section
+			if section == "" {
+				section = "introduction"
+			}
+			lines = append(lines, fmt.Sprintf("   linked as %q in section %q", link.AnchorText, section))
+			if link.Snippet != "" {
+				lines = append(lines, fmt.Sprintf("   %s", link.Snippet))
+			}
+		}
+	}
+
+	lines = append(lines,
+		fmt.Sprintf("Visited %d pages", result.FetchedPages),
+		fmt.Sprintf("Discovered %d unique links during search", result.DiscoveredPages),
+	)
+
+	_, err = fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return
+}
@@ -0,0 +1,295 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+var (
+	foundResult = SearchResult{
+		Start:  "https://en.wikipedia.org/wiki/Times_New_Roman",
+		Target: "https://en.wikipedia.org/wiki/The_Times",
+		Found:  true,
+		Path: []Page{
+			{Title: "Times New Roman", URL: "https://en.wikipedia.org/wiki/Times_New_Roman"},
+			{Title: "The Times", URL: "https://en.wikipedia.org/wiki/The_Times"},
+		},
+		Hops:            1,
+		DurationMillis:  42,
+		FetchedPages:    1,
+		DiscoveredPages: 334,
+	}
+	linkContextResult = SearchResult{
+		Start:  "https://en.wikipedia.org/wiki/Times_New_Roman",
+		Target: "https://en.wikipedia.org/wiki/The_Times",
+		Found:  true,
+		Path: []Page{
+			{Title: "Times New Roman", URL: "https://en.wikipedia.org/wiki/Times_New_Roman"},
+			{
+				Title: "The Times",
+				URL:   "https://en.wikipedia.org/wiki/The_Times",
+				Link: &Link{
+					AnchorText: "The Times",
+					Snippet:    "It was commissioned by The Times, in 1931.",
+				},
+			},
+		},
+		Hops:            1,
+		DurationMillis:  42,
+		FetchedPages:    1,
+		DiscoveredPages: 334,
+	}
+	interlanguageResult = SearchResult{
+		Start:  "https://de.wikipedia.org/wiki/Schriftart",
+		Target: "https://en.wikipedia.org/wiki/Typeface",
+		Found:  true,
+		Path: []Page{
+			{Title: "Schriftart", URL: "https://de.wikipedia.org/wiki/Schriftart"},
+			{Title: "en:Typeface", URL: "https://en.wikipedia.org/wiki/Typeface", Interlanguage: true},
+		},
+		Hops:            1,
+		DurationMillis:  42,
+		FetchedPages:    1,
+		DiscoveredPages: 12,
+	}
+	notFoundResult = SearchResult{
+		Start:           "https://en.wikipedia.org/wiki/Times_New_Roman",
+		Target:          "https://en.wikipedia.org/wiki/Great_Britain",
+		Error:           "reached max hops",
+		Path:            make([]Page, 0),
+		DurationMillis:  1337,
+		FetchedPages:    335,
+		DiscoveredPages: 12345,
+	}
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		wantFormat Format
+		wantErr    bool
+	}{
+		{
+			name:       "parse json",
+			value:      "json",
+			wantFormat: FormatJSON,
+		},
+		{
+			name:       "parse format case insensitive",
+			value:      "YAML",
+			wantFormat: FormatYAML,
+		},
+		{
+			name:    "parse unknown format",
+			value:   "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFormat, err := ParseFormat(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFormat() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && gotFormat != tt.wantFormat {
+				t.Errorf("ParseFormat() = %v, want %v", gotFormat, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  Format
+		result  SearchResult
+		wantOut string
+		wantErr bool
+	}{
+		{
+			name:   "write found result as JSON",
+			format: FormatJSON,
+			result: foundResult,
+			wantOut: `{
+  "start": "https://en.wikipedia.org/wiki/Times_New_Roman",
+  "target": "https://en.wikipedia.org/wiki/The_Times",
+  "found": true,
+  "path": [
+    {
+      "title": "Times New Roman",
+      "url": "https://en.wikipedia.org/wiki/Times_New_Roman"
+    },
+    {
+      "title": "The Times",
+      "url": "https://en.wikipedia.org/wiki/The_Times"
+    }
+  ],
+  "hops": 1,
+  "duration_ms": 42,
+  "fetched_pages": 1,
+  "discovered_pages": 334
+}
+`,
+		},
+		{
+			name:   "write not found result as JSON",
+			format: FormatJSON,
+			result: notFoundResult,
+			wantOut: `{
+  "start": "https://en.wikipedia.org/wiki/Times_New_Roman",
+  "target": "https://en.wikipedia.org/wiki/Great_Britain",
+  "found": false,
+  "error": "reached max hops",
+  "path": [],
+  "hops": 0,
+  "duration_ms": 1337,
+  "fetched_pages": 335,
+  "discovered_pages": 12345
+}
+`,
+		},
+		{
+			name:   "write found result as YAML",
+			format: FormatYAML,
+			result: foundResult,
+			wantOut: `start: https://en.wikipedia.org/wiki/Times_New_Roman
+target: https://en.wikipedia.org/wiki/The_Times
+found: true
+path:
+- title: Times New Roman
+  url: https://en.wikipedia.org/wiki/Times_New_Roman
+- title: The Times
+  url: https://en.wikipedia.org/wiki/The_Times
+hops: 1
+duration_ms: 42
+fetched_pages: 1
+discovered_pages: 334
+`,
+		},
+		{
+			name:   "write found result as CSV",
+			format: FormatCSV,
+			result: foundResult,
+			wantOut: `position,title,url,found,error,hops,duration_ms,fetched_pages,discovered_pages,anchor_text,section,snippet,interlanguage
+0,Times New Roman,https://en.wikipedia.org/wiki/Times_New_Roman,true,,1,42,1,334,,,,false
+1,The Times,https://en.wikipedia.org/wiki/The_Times,true,,1,42,1,334,,,,false
+`,
+		},
+		{
+			name:   "write not found result as CSV",
+			format: FormatCSV,
+			result: notFoundResult,
+			wantOut: `position,title,url,found,error,hops,duration_ms,fetched_pages,discovered_pages,anchor_text,section,snippet,interlanguage
+,,,false,reached max hops,0,1337,335,12345,,,,false
+`,
+		},
+		{
+			name:   "write found result as text",
+			format: FormatText,
+			result: foundResult,
+			wantOut: `Resolved path with 1 hops in 42 ms
+0. Times New Roman (https://en.wikipedia.org/wiki/Times_New_Roman)
+1. The Times (https://en.wikipedia.org/wiki/The_Times)
+Visited 1 pages
+Discovered 334 unique links during search
+`,
+		},
+		{
+			name:   "write result with link context as JSON",
+			format: FormatJSON,
+			result: linkContextResult,
+			wantOut: `{
+  "start": "https://en.wikipedia.org/wiki/Times_New_Roman",
+  "target": "https://en.wikipedia.org/wiki/The_Times",
+  "found": true,
+  "path": [
+    {
+      "title": "Times New Roman",
+      "url": "https://en.wikipedia.org/wiki/Times_New_Roman"
+    },
+    {
+      "title": "The Times",
+      "url": "https://en.wikipedia.org/wiki/The_Times",
+      "link": {
+        "anchor_text": "The Times",
+        "snippet": "It was commissioned by The Times, in 1931."
+      }
+    }
+  ],
+  "hops": 1,
+  "duration_ms": 42,
+  "fetched_pages": 1,
+  "discovered_pages": 334
+}
+`,
+		},
+		{
+			name:   "write result with link context as CSV",
+			format: FormatCSV,
+			result: linkContextResult,
+			wantOut: `position,title,url,found,error,hops,duration_ms,fetched_pages,discovered_pages,anchor_text,section,snippet,interlanguage
+0,Times New Roman,https://en.wikipedia.org/wiki/Times_New_Roman,true,,1,42,1,334,,,,false
+1,The Times,https://en.wikipedia.org/wiki/The_Times,true,,1,42,1,334,The Times,,"It was commissioned by The Times, in 1931.",false
+`,
+		},
+		{
+			name:   "write result with link context as text",
+			format: FormatText,
+			result: linkContextResult,
+			wantOut: `Resolved path with 1 hops in 42 ms
+0. Times New Roman (https://en.wikipedia.org/wiki/Times_New_Roman)
+1. The Times (https://en.wikipedia.org/wiki/The_Times)
+   linked as "The Times" in section "introduction"
+   It was commissioned by The Times, in 1931.
+Visited 1 pages
+Discovered 334 unique links during search
+`,
+		},
+		{
+			name:   "write result with interlanguage link as text",
+			format: FormatText,
+			result: interlanguageResult,
+			wantOut: `Resolved path with 1 hops in 42 ms
+0. Schriftart (https://de.wikipedia.org/wiki/Schriftart)
+1. en:Typeface (https://en.wikipedia.org/wiki/Typeface)
+   reached via interlanguage link
+Visited 1 pages
+Discovered 12 unique links during search
+`,
+		},
+		{
+			name:    "write unknown format",
+			format:  Format("xml"),
+			result:  foundResult,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if err := Write(out, tt.format, tt.result); (err != nil) != tt.wantErr {
+				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotOut := out.String(); !tt.wantErr && gotOut != tt.wantOut {
+				t.Errorf("Write() = %v, want %v", gotOut, tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	"gopkg.in/yaml.v2"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+var (
+	linksCSVHeader = []string{"kept", "reason", "region", "section", "namespace", "kind", "title", "url", "href", "anchor_text"}
+)
+
+// LinkReport explains the decision about a single link of an inspected page
+type LinkReport struct {
+	Kept       bool   `json:"kept" yaml:"kept"`
+	Reason     string `json:"reason" yaml:"reason"`
+	Region     string `json:"region" yaml:"region"`
+	Section    string `json:"section,omitempty" yaml:"section,omitempty"`
+	Namespace  string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Kind       string `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Title      string `json:"title,omitempty" yaml:"title,omitempty"`
+	URL        string `json:"url,omitempty" yaml:"url,omitempty"`
+	Href       string `json:"href" yaml:"href"`
+	AnchorText string `json:"anchor_text" yaml:"anchor_text"`
+}
+
+// NewLinkReports converts the decisions of a link inspection, dropped links are skipped if keptOnly is set
+func NewLinkReports(decisions []crawling.LinkDecision, keptOnly bool) (reports []LinkReport) {
+	reports = make([]LinkReport, 0, len(decisions))
+	for _, decision := range decisions {
+		if keptOnly && !decision.Kept {
+			continue
+		}
+		report := LinkReport{
+			Kept:       decision.Kept,
+			Reason:     decision.Reason,
+			Region:     decision.Region,
+			Section:    decision.Section,
+			Namespace:  decision.Namespace,
+			Title:      decision.Title,
+			URL:        decision.URI,
+			Href:       decision.Href,
+			AnchorText: decision.AnchorText,
+		}
+		if decision.URI != "" {
+			report.Kind = decision.Kind.String()
+		}
+		reports = append(reports, report)
+	}
+	return
+}
+
+// WriteLinks writes the link reports to w in the given format
+func WriteLinks(w io.Writer, format Format, reports []LinkReport) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(reports)
+	case FormatYAML:
+		return yaml.NewEncoder(w).Encode(reports)
+	case FormatCSV:
+		return writeLinksCSV(w, reports)
+	case FormatText:
+		return writeLinksText(w, reports)
+	default:
+		return fmt.Errorf("unknown output format %s", format)
+	}
+}
+
+func writeLinksCSV(w io.Writer, reports []LinkReport) (err error) {
+	writer := csv.NewWriter(w)
+	if err = writer.Write(linksCSVHeader); err != nil {
+		return
+	}
+
+	for _, report := range reports {
+		row := []string{
+			strconv.FormatBool(report.Kept), report.Reason, report.Region, report.Section, report.Namespace,
+			report.Kind, report.Title, report.URL, report.Href, report.AnchorText,
+		}
+		if err = writer.Write(row); err != nil {
+			return
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeLinksText(w io.Writer, reports []LinkReport) (err error) {
+	writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err = fmt.Fprintln(writer, "DECISION\tREGION\tSECTION\tLINK\tREASON"); err != nil {
+		return
+	}
+
+	kept := 0
+	for _, report := range reports {
+		decision := "drop"
+		if report.Kept {
+			decision = "keep"
+			kept++
+		}
+
+		link := report.Href
+		if report.Title != "" {
+			link = report.Title
+		}
+		if _, err = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", decision, report.Region, report.Section, link, report.Reason); err != nil {
+			return
+		}
+	}
+
+	if err = writer.Flush(); err != nil {
+		return
+	}
+
+	_, err = fmt.Fprintf(w, "Kept %d of %d links\n", kept, len(reports))
+	return
+}
@@ -0,0 +1,107 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"bytes"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	"testing"
+)
+
+var (
+	linkDecisions = []crawling.LinkDecision{
+		{
+			Href:       "/wiki/Serif",
+			AnchorText: "serif",
+			Title:      "Serif",
+			URI:        "https://en.wikipedia.org/wiki/Serif",
+			Region:     crawling.ContentRegion,
+			Section:    "History",
+			Kept:       true,
+			Reason:     "article link",
+		},
+		{
+			Href:       "/wiki/File:Times_New_Roman-sample.svg",
+			AnchorText: "sample",
+			Namespace:  "File",
+			Region:     crawling.ContentRegion,
+			Reason:     "non-article namespace File",
+		},
+	}
+)
+
+func TestWriteLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   Format
+		keptOnly bool
+		wantOut  string
+		wantErr  bool
+	}{
+		{
+			name:   "write links as text",
+			format: FormatText,
+			wantOut: `DECISION  REGION   SECTION  LINK                                   REASON
+keep      content  History  Serif                                  article link
+drop      content           /wiki/File:Times_New_Roman-sample.svg  non-article namespace File
+Kept 1 of 2 links
+`,
+		},
+		{
+			name:     "write kept links as CSV",
+			format:   FormatCSV,
+			keptOnly: true,
+			wantOut: `kept,reason,region,section,namespace,kind,title,url,href,anchor_text
+true,article link,content,History,,article,Serif,https://en.wikipedia.org/wiki/Serif,/wiki/Serif,serif
+`,
+		},
+		{
+			name:     "write kept links as JSON",
+			format:   FormatJSON,
+			keptOnly: true,
+			wantOut: `[
+  {
+    "kept": true,
+    "reason": "article link",
+    "region": "content",
+    "section": "History",
+    "kind": "article",
+    "title": "Serif",
+    "url": "https://en.wikipedia.org/wiki/Serif",
+    "href": "/wiki/Serif",
+    "anchor_text": "serif"
+  }
+]
+`,
+		},
+		{
+			name:    "write unknown format",
+			format:  Format("xml"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if err := WriteLinks(out, tt.format, NewLinkReports(linkDecisions, tt.keptOnly)); (err != nil) != tt.wantErr {
+				t.Errorf("WriteLinks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotOut := out.String(); !tt.wantErr && gotOut != tt.wantOut {
+				t.Errorf("WriteLinks() = %v, want %v", gotOut, tt.wantOut)
+			}
+		})
+	}
+}
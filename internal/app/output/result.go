@@ -0,0 +1,94 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"github.com/baez90/shortest-path/pkg/crawling"
+	"time"
+)
+
+// Page is a single page on the resolved path
+type Page struct {
+	Title string `json:"title" yaml:"title"`
+	URL   string `json:"url" yaml:"url"`
+	// Link is the link on the previous page leading to this page, only available if link context capturing is enabled
+	Link *Link `json:"link,omitempty" yaml:"link,omitempty"`
+	// Interlanguage is set if the page was reached by an interlanguage link from the previous page
+	Interlanguage bool `json:"interlanguage,omitempty" yaml:"interlanguage,omitempty"`
+}
+
+// Link describes where on the previous page the link to a page appears
+type Link struct {
+	AnchorText string `json:"anchor_text" yaml:"anchor_text"`
+	Section    string `json:"section,omitempty" yaml:"section,omitempty"`
+	Snippet    string `json:"snippet,omitempty" yaml:"snippet,omitempty"`
+}
+
+// SearchResult is the outcome of a single search as written by all output formats
+type SearchResult struct {
+	Start           string `json:"start" yaml:"start"`
+	Target          string `json:"target" yaml:"target"`
+	Found           bool   `json:"found" yaml:"found"`
+	Error           string `json:"error,omitempty" yaml:"error,omitempty"`
+	Path            []Page `json:"path" yaml:"path"`
+	Hops            int    `json:"hops" yaml:"hops"`
+	DurationMillis  int64  `json:"duration_ms" yaml:"duration_ms"`
+	FetchedPages    uint   `json:"fetched_pages" yaml:"fetched_pages"`
+	DiscoveredPages int    `json:"discovered_pages" yaml:"discovered_pages"`
+}
+
+// NewSearchResult collects the result of a finished search.
+// searchErr is the error returned by the search, if any the result is marked as not found.
+func NewSearchResult(crawler *crawling.WikiCrawler, traversalResult crawling.TraversalResult, searchErr error, duration time.Duration) (result SearchResult) {
+	result = SearchResult{
+		Start:           crawler.StartPage(),
+		Target:          crawler.TargetPage(),
+		Path:            make([]Page, 0),
+		DurationMillis:  duration.Milliseconds(),
+		FetchedPages:    crawler.FetchedPages(),
+		DiscoveredPages: crawler.DiscoveredPages(),
+	}
+
+	if searchErr != nil {
+		result.Error = searchErr.Error()
+		return
+	}
+
+	result.Found = true
+	result.Hops = traversalResult.Hops()
+	result.Path = NewPath(traversalResult.Path())
+	return
+}
+
+// NewPath converts the pages of a resolved path
+func NewPath(pages []crawling.PathPage) (path []Page) {
+	path = make([]Page, 0, len(pages))
+	for _, page := range pages {
+		resultPage := Page{
+			Title:         page.Title,
+			URL:           page.URI,
+			Interlanguage: page.Interlanguage,
+		}
+		if page.Link != nil {
+			resultPage.Link = &Link{
+				AnchorText: page.Link.AnchorText,
+				Section:    page.Link.Section,
+				Snippet:    page.Link.Snippet,
+			}
+		}
+		path = append(path, resultPage)
+	}
+	return
+}
@@ -0,0 +1,112 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/baez90/shortest-path/internal/app/jobs"
+	"net/http"
+	"time"
+)
+
+const (
+	eventsPathSuffix = "/events"
+	// keepAliveInterval is the maximum time without any message on an event stream so that proxies keep it open
+	keepAliveInterval = 15 * time.Second
+)
+
+// handleJobEvents streams the progress of the job as Server-Sent Events.
+// The stream starts with a job event with the current state, followed by the events of the search
+// and ends with a finished event carrying the final state and the result of the job.
+func (server *Server) handleJobEvents(w http.ResponseWriter, r *http.Request, id string) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported"))
+		return
+	}
+
+	events, unsubscribe, err := server.jobs.Subscribe(id)
+	if err != nil {
+		writeJobError(w, id, err)
+		return
+	}
+	defer unsubscribe()
+
+	job, err := server.jobs.Get(id)
+	if err != nil {
+		writeJobError(w, id, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if err = writeEvent(w, flusher, "job", job); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event, open := <-events:
+			if !open {
+				if job, err = server.jobs.Get(id); err == nil {
+					_ = writeEvent(w, flusher, "finished", job)
+				}
+				return
+			}
+			err = writeEvent(w, flusher, string(event.Type), event)
+		case <-keepAlive.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, flusher http.Flusher, name string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if _, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+// writeJobError responds with 404 for unknown jobs, with 500 otherwise
+func writeJobError(w http.ResponseWriter, id string, err error) {
+	if err == jobs.ErrJobNotFound {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%w: %s", err, id))
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err)
+}
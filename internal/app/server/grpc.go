@@ -0,0 +1,233 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	shortestpathv1 "github.com/baez90/shortest-path/api/shortestpath/v1"
+	"github.com/baez90/shortest-path/internal/app/output"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"math"
+	"net/http"
+	"time"
+)
+
+const (
+	// progressUpdateInterval is the minimum time between two page_processed updates of WatchSearch
+	progressUpdateInterval = 500 * time.Millisecond
+)
+
+// grpcService answers the queries of the gRPC API with the same settings and page cache as the JSON API
+type grpcService struct {
+	shortestpathv1.UnimplementedShortestPathServer
+	server *Server
+}
+
+// RegisterGRPC registers the ShortestPath service of shortest_path.proto at the given gRPC server
+// together with the reflection service to let tools like grpcurl discover it
+func (server *Server) RegisterGRPC(grpcServer *grpc.Server) {
+	shortestpathv1.RegisterShortestPathServer(grpcServer, &grpcService{server: server})
+	reflection.Register(grpcServer)
+}
+
+// FindPath searches the shortest path like POST /v1/paths, failed searches are reported as error status
+func (service *grpcService) FindPath(ctx context.Context, request *shortestpathv1.FindPathRequest) (*shortestpathv1.SearchResult, error) {
+	crawler, err := service.server.pathCrawler(newPathRequest(request))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	result, err := service.server.search(ctx, crawler)
+	if err != nil {
+		return nil, searchStatus(err)
+	}
+	return newSearchResultMessage(result), nil
+}
+
+// GetLinks explains the links of a page like GET /v1/links/{title}
+func (service *grpcService) GetLinks(ctx context.Context, request *shortestpathv1.GetLinksRequest) (*shortestpathv1.GetLinksResponse, error) {
+	reports, err := service.server.linkReports(ctx, request.Title, request.Wiki, request.Lang, request.Interlanguage, request.KeptOnly)
+	if err != nil {
+		return nil, queryStatus(err)
+	}
+
+	response := &shortestpathv1.GetLinksResponse{
+		Links: make([]*shortestpathv1.LinkReport, 0, len(reports)),
+	}
+	for _, report := range reports {
+		response.Links = append(response.Links, &shortestpathv1.LinkReport{
+			Kept:       report.Kept,
+			Reason:     report.Reason,
+			Region:     report.Region,
+			Section:    report.Section,
+			Namespace:  report.Namespace,
+			Kind:       report.Kind,
+			Title:      report.Title,
+			Url:        report.URL,
+			Href:       report.Href,
+			AnchorText: report.AnchorText,
+		})
+	}
+	return response, nil
+}
+
+// WatchSearch searches the shortest path and streams every step of the search, page_processed updates are throttled.
+// The last update of a successful stream carries the result.
+func (service *grpcService) WatchSearch(request *shortestpathv1.FindPathRequest, stream shortestpathv1.ShortestPath_WatchSearchServer) error {
+	crawler, err := service.server.pathCrawler(newPathRequest(request), crawling.WithObserver(&streamObserver{stream: stream}))
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	result, err := service.server.search(stream.Context(), crawler)
+	if err != nil {
+		return searchStatus(err)
+	}
+	return stream.Send(&shortestpathv1.SearchUpdate{Result: newSearchResultMessage(result)})
+}
+
+// streamObserver sends the events of the search to the client of WatchSearch, page_processed updates are throttled
+type streamObserver struct {
+	crawling.NopObserver
+	stream             shortestpathv1.ShortestPath_WatchSearchServer
+	lastProgressUpdate time.Time
+}
+
+func (observer *streamObserver) OnLevelStart(event crawling.SearchEvent) {
+	observer.send(event)
+}
+
+func (observer *streamObserver) OnLevelFinished(event crawling.SearchEvent) {
+	observer.send(event)
+}
+
+func (observer *streamObserver) OnPageProcessed(event crawling.SearchEvent) {
+	if time.Since(observer.lastProgressUpdate) < progressUpdateInterval {
+		return
+	}
+	observer.lastProgressUpdate = time.Now()
+	observer.send(event)
+}
+
+func (observer *streamObserver) OnPathFound(event crawling.SearchEvent) {
+	observer.send(event)
+}
+
+func (observer *streamObserver) send(event crawling.SearchEvent) {
+	if err := observer.stream.Send(&shortestpathv1.SearchUpdate{Progress: newSearchProgressMessage(event)}); err != nil {
+		log.
+			WithError(err).
+			Debug("Failed to send search progress")
+	}
+}
+
+// newPathRequest converts the request message, max hops exceeding the range of uint16 are saturated to be rejected by the hop limit
+func newPathRequest(request *shortestpathv1.FindPathRequest) PathRequest {
+	maxHops := uint16(math.MaxUint16)
+	if request.MaxHops < math.MaxUint16 {
+		maxHops = uint16(request.MaxHops)
+	}
+	return PathRequest{
+		Start:         request.Start,
+		Target:        request.Target,
+		Wiki:          request.Wiki,
+		Lang:          request.Lang,
+		MaxHops:       maxHops,
+		Interlanguage: request.Interlanguage,
+		LinkContext:   request.LinkContext,
+	}
+}
+
+// searchStatus converts the error of a search to the status of the gRPC call
+func searchStatus(err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// queryStatus converts the HTTP status of a queryError to the status of the gRPC call
+func queryStatus(err error) error {
+	var queryErr *queryError
+	if !errors.As(err, &queryErr) {
+		return status.Error(codes.Internal, err.Error())
+	}
+	switch queryErr.status {
+	case http.StatusBadRequest:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case http.StatusBadGateway:
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func newSearchResultMessage(result output.SearchResult) *shortestpathv1.SearchResult {
+	return &shortestpathv1.SearchResult{
+		Start:           result.Start,
+		Target:          result.Target,
+		Found:           result.Found,
+		Error:           result.Error,
+		Path:            newPathMessage(result.Path),
+		Hops:            int32(result.Hops),
+		DurationMs:      result.DurationMillis,
+		FetchedPages:    uint64(result.FetchedPages),
+		DiscoveredPages: int64(result.DiscoveredPages),
+	}
+}
+
+func newSearchProgressMessage(event crawling.SearchEvent) *shortestpathv1.SearchProgress {
+	return &shortestpathv1.SearchProgress{
+		Type:             string(event.Kind),
+		Depth:            uint32(event.Progress.Depth),
+		FrontierSize:     event.Progress.FrontierSize,
+		ProcessedPages:   event.Progress.ProcessedPages,
+		NextFrontierSize: event.Progress.NextFrontierSize,
+		FetchedPages:     uint64(event.Progress.FetchedPages),
+		DiscoveredPages:  int64(event.Progress.DiscoveredPages),
+		ElapsedMs:        event.Elapsed.Milliseconds(),
+		PagesPerSecond:   event.PagesPerSecond,
+		Path:             newPathMessage(output.NewPath(event.Path)),
+	}
+}
+
+func newPathMessage(path []output.Page) (pages []*shortestpathv1.Page) {
+	for _, page := range path {
+		message := &shortestpathv1.Page{
+			Title:         page.Title,
+			Url:           page.URL,
+			Interlanguage: page.Interlanguage,
+		}
+		if page.Link != nil {
+			message.Link = &shortestpathv1.Link{
+				AnchorText: page.Link.AnchorText,
+				Section:    page.Link.Section,
+				Snippet:    page.Link.Snippet,
+			}
+		}
+		pages = append(pages, message)
+	}
+	return
+}
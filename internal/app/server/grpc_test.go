@@ -0,0 +1,239 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	shortestpathv1 "github.com/baez90/shortest-path/api/shortestpath/v1"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"io"
+	"net"
+	"testing"
+)
+
+// newTestClient serves the gRPC API of the server via an in-process listener until stop is called
+func newTestClient(t *testing.T, server *Server) (client shortestpathv1.ShortestPathClient, stop func()) {
+	t.Helper()
+	conn, stop := dialTestServer(t, server)
+	return shortestpathv1.NewShortestPathClient(conn), stop
+}
+
+// dialTestServer serves the gRPC API of the server via an in-process listener until stop is called
+func dialTestServer(t *testing.T, server *Server) (conn *grpc.ClientConn, stop func()) {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	server.RegisterGRPC(grpcServer)
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+
+	conn, err := grpc.Dial(
+		"bufnet",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop = func() {
+		_ = conn.Close()
+		grpcServer.Stop()
+	}
+	return
+}
+
+func TestServer_RegisterGRPC_reflection(t *testing.T) {
+	conn, stop := dialTestServer(t, newTestServer(newTestWiki()))
+	defer stop()
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.CloseSend()
+
+	if err = stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: "shortestpath.v1.ShortestPath"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	response, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	descriptors := response.GetFileDescriptorResponse().GetFileDescriptorProto()
+	if len(descriptors) == 0 {
+		t.Fatalf("reflection did not resolve the service: %v", response.GetErrorResponse())
+	}
+	file := &descriptorpb.FileDescriptorProto{}
+	if err = proto.Unmarshal(descriptors[0], file); err != nil {
+		t.Fatal(err)
+	}
+	if file.GetName() != "shortestpath/v1/shortest_path.proto" || len(file.GetService()) != 1 || len(file.GetService()[0].GetMethod()) != 3 {
+		t.Errorf("reflection resolved %s with services %v", file.GetName(), file.GetService())
+	}
+}
+
+func TestGRPCService_FindPath(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   *shortestpathv1.FindPathRequest
+		wantCode  codes.Code
+		wantFound bool
+		wantPath  []string
+	}{
+		{
+			name:      "find path between titles",
+			request:   &shortestpathv1.FindPathRequest{Start: "Alpha", Target: "Delta"},
+			wantFound: true,
+			wantPath:  []string{"Alpha", "Beta", "Gamma", "Delta"},
+		},
+		{
+			name:    "report target out of reach as not found",
+			request: &shortestpathv1.FindPathRequest{Start: "Alpha", Target: "Delta", MaxHops: 2},
+		},
+		{
+			name:     "reject more hops than allowed by the server",
+			request:  &shortestpathv1.FindPathRequest{Start: "Alpha", Target: "Delta", MaxHops: 1 << 16},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "reject missing target",
+			request:  &shortestpathv1.FindPathRequest{Start: "Alpha"},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, stop := newTestClient(t, newTestServer(newTestWiki()))
+			defer stop()
+			result, err := client.FindPath(context.Background(), tt.request)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("FindPath() code = %v, want %v: %v", code, tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+			if result.Found != tt.wantFound {
+				t.Errorf("FindPath() found = %v, want %v", result.Found, tt.wantFound)
+			}
+			if len(result.Path) != len(tt.wantPath) {
+				t.Fatalf("FindPath() path = %v, want %v", result.Path, tt.wantPath)
+			}
+			for idx, page := range result.Path {
+				if page.Title != tt.wantPath[idx] {
+					t.Errorf("page %d = %q, want %q", idx, page.Title, tt.wantPath[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestGRPCService_GetLinks(t *testing.T) {
+	tests := []struct {
+		name       string
+		request    *shortestpathv1.GetLinksRequest
+		wantCode   codes.Code
+		wantTitles []string
+	}{
+		{
+			name:       "list all links",
+			request:    &shortestpathv1.GetLinksRequest{Title: "Alpha"},
+			wantTitles: []string{"Beta", ""},
+		},
+		{
+			name:       "list kept links only",
+			request:    &shortestpathv1.GetLinksRequest{Title: "Alpha", KeptOnly: true},
+			wantTitles: []string{"Beta"},
+		},
+		{
+			name:     "reject missing title",
+			request:  &shortestpathv1.GetLinksRequest{},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "report unavailable page",
+			request:  &shortestpathv1.GetLinksRequest{Title: "Omega"},
+			wantCode: codes.Unavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, stop := newTestClient(t, newTestServer(newTestWiki()))
+			defer stop()
+			response, err := client.GetLinks(context.Background(), tt.request)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("GetLinks() code = %v, want %v: %v", code, tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(response.Links) != len(tt.wantTitles) {
+				t.Fatalf("GetLinks() got %d links, want %d", len(response.Links), len(tt.wantTitles))
+			}
+			for idx, report := range response.Links {
+				if report.Title != tt.wantTitles[idx] {
+					t.Errorf("link %d = %q, want %q", idx, report.Title, tt.wantTitles[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestGRPCService_WatchSearch(t *testing.T) {
+	client, stop := newTestClient(t, newTestServer(newTestWiki()))
+	defer stop()
+	stream, err := client.WatchSearch(context.Background(), &shortestpathv1.FindPathRequest{Start: "Alpha", Target: "Delta"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var updates []*shortestpathv1.SearchUpdate
+	for {
+		update, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		updates = append(updates, update)
+	}
+
+	if len(updates) < 2 {
+		t.Fatalf("got %d updates, want progress and result", len(updates))
+	}
+	if first := updates[0].Progress; first == nil || first.Type != "level_started" {
+		t.Errorf("first update = %v, want level_started", updates[0])
+	}
+	pathFound := updates[len(updates)-2].Progress
+	if pathFound == nil || pathFound.Type != "path_found" || len(pathFound.Path) != 4 {
+		t.Errorf("second to last update = %v, want path_found with 4 pages", updates[len(updates)-2])
+	}
+	result := updates[len(updates)-1].Result
+	if result == nil || !result.Found || result.Hops != 3 {
+		t.Errorf("last update = %v, want result with 3 hops", updates[len(updates)-1])
+	}
+}
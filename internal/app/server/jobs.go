@@ -0,0 +1,99 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/baez90/shortest-path/internal/app/jobs"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	"net/http"
+	"strings"
+)
+
+const (
+	jobsPath       = "/v1/jobs"
+	jobsPathPrefix = jobsPath + "/"
+)
+
+// EnableJobs lets clients submit searches as jobs running in the background:
+// POST /v1/jobs submits a search with the same body as POST /v1/paths, GET /v1/jobs/{id} polls its state
+// and DELETE /v1/jobs/{id} cancels it or deletes it if it is already finished.
+// GET /v1/jobs/{id}/events streams the progress of the job as Server-Sent Events.
+func (server *Server) EnableJobs(manager *jobs.Manager) {
+	server.jobs = manager
+	server.mux.HandleFunc(jobsPath, server.handleSubmitJob)
+	server.mux.HandleFunc(jobsPathPrefix, server.handleJob)
+}
+
+func (server *Server) handleSubmitJob(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var request PathRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid path request: %w", err))
+		return
+	}
+
+	// invalid requests are detected while the crawler of the job is created
+	var requestErr error
+	job, err := server.jobs.Submit(func(options ...crawling.Option) (crawler *crawling.WikiCrawler, err error) {
+		crawler, err = server.pathCrawler(request, options...)
+		requestErr = err
+		return
+	})
+	switch {
+	case requestErr != nil:
+		writeError(w, http.StatusBadRequest, requestErr)
+	case err == jobs.ErrQueueFull:
+		writeError(w, http.StatusServiceUnavailable, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		w.Header().Set("Location", jobsPathPrefix+job.ID)
+		writeJSON(w, http.StatusAccepted, job)
+	}
+}
+
+func (server *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, jobsPathPrefix)
+	if strings.HasSuffix(id, eventsPathSuffix) {
+		server.handleJobEvents(w, r, strings.TrimSuffix(id, eventsPathSuffix))
+		return
+	}
+
+	var job jobs.Job
+	var err error
+	switch r.Method {
+	case http.MethodGet:
+		job, err = server.jobs.Get(id)
+	case http.MethodDelete:
+		job, err = server.jobs.Cancel(id)
+	default:
+		w.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodDelete}, ", "))
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed, use GET or DELETE", r.Method))
+		return
+	}
+
+	if err != nil {
+		writeJobError(w, id, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
@@ -0,0 +1,96 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/baez90/shortest-path/internal/app/output"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	linksPathPrefix = "/v1/links/"
+)
+
+// handleLinks explains which links of the page GET /v1/links/{title} the search follows.
+// The query parameters wiki and lang select the wiki, interlanguage and kept_only behave like the flags of the links command.
+func (server *Server) handleLinks(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	query := r.URL.Query()
+	title := strings.TrimPrefix(r.URL.Path, linksPathPrefix)
+
+	interlanguage, err := boolParameter(query.Get("interlanguage"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid interlanguage parameter: %w", err))
+		return
+	}
+	keptOnly, err := boolParameter(query.Get("kept_only"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid kept_only parameter: %w", err))
+		return
+	}
+
+	reports, err := server.linkReports(r.Context(), title, query.Get("wiki"), query.Get("lang"), interlanguage, keptOnly)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, reports)
+}
+
+// linkReports inspects the links of the page with the given title in the selected wiki.
+// Invalid titles or wikis are reported as bad request, pages which cannot be fetched or parsed as bad gateway.
+func (server *Server) linkReports(ctx context.Context, title, wiki, lang string, interlanguage, keptOnly bool) (reports []output.LinkReport, err error) {
+	if strings.TrimSpace(title) == "" {
+		return nil, &queryError{status: http.StatusBadRequest, err: fmt.Errorf("title is required")}
+	}
+
+	defaultWiki, err := server.defaultWiki(wiki, lang)
+	if err != nil {
+		return nil, &queryError{status: http.StatusBadRequest, err: err}
+	}
+
+	page, err := crawling.ResolvePage(title, defaultWiki, server.config.SiteProfiles)
+	if err != nil {
+		return nil, &queryError{status: http.StatusBadRequest, err: err}
+	}
+
+	body, err := server.cache.Fetch(ctx, page.URI())
+	if err != nil {
+		return nil, &queryError{status: http.StatusBadGateway, err: err}
+	}
+	defer body.Close()
+
+	decisions, err := crawling.InspectLinks(body, page, interlanguage)
+	if err != nil {
+		return nil, &queryError{status: http.StatusBadGateway, err: fmt.Errorf("failed to parse %s: %w", page.URI(), err)}
+	}
+	return output.NewLinkReports(decisions, keptOnly), nil
+}
+
+// boolParameter parses an optional boolean query parameter, absent parameters are false
+func boolParameter(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
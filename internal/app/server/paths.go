@@ -0,0 +1,158 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/baez90/shortest-path/internal/app/output"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+)
+
+// PathRequest is the body of a path query, start and target are article URLs or titles
+type PathRequest struct {
+	Start  string `json:"start"`
+	Target string `json:"target"`
+	// Wiki is the base URL of the wiki to resolve titles in, takes precedence over Lang
+	Wiki string `json:"wiki,omitempty"`
+	// Lang is the language of the Wikipedia to resolve titles in
+	Lang string `json:"lang,omitempty"`
+	// MaxHops limits the depth of the search, 0 applies the limit of the server
+	MaxHops       uint16 `json:"max_hops,omitempty"`
+	Interlanguage bool   `json:"interlanguage,omitempty"`
+	LinkContext   bool   `json:"link_context,omitempty"`
+}
+
+// handlePaths searches the shortest path of a single query and responds with the same result as the CLI's JSON output.
+// A search not reaching the target within max hops is no failure of the request, the result is just not found.
+func (server *Server) handlePaths(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var request PathRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid path request: %w", err))
+		return
+	}
+
+	crawler, err := server.pathCrawler(request)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := server.search(r.Context(), crawler)
+	status := http.StatusOK
+	switch {
+	case errors.Is(err, context.Canceled):
+		// the client went away, there is nobody left to respond to
+		return
+	case errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusGatewayTimeout
+	case err != nil:
+		status = http.StatusInternalServerError
+	}
+	writeJSON(w, status, result)
+}
+
+// search runs the search of the crawler limited by the search timeout of the server.
+// err is only set if the search failed, a search not reaching the target within max hops is a regular not found result.
+// It is the error of the context if the search was cancelled or timed out.
+func (server *Server) search(ctx context.Context, crawler *crawling.WikiCrawler) (result output.SearchResult, err error) {
+	if server.config.SearchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, server.config.SearchTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	res, searchErr := crawler.SearchShortestPath(ctx)
+	result = output.NewSearchResult(crawler, res, searchErr, time.Since(start))
+
+	switch {
+	case searchErr == nil, searchErr == crawling.ErrMaxHopsReached:
+	case ctx.Err() != nil:
+		err = ctx.Err()
+	default:
+		log.
+			WithError(searchErr).
+			Errorf("Failed to resolve shortest path %s -> %s", crawler.StartPage(), crawler.TargetPage())
+		err = searchErr
+	}
+	return
+}
+
+// pathCrawler resolves the pages of the request and sets up a crawler used for this request only,
+// extraOptions are applied after the options derived from the request
+func (server *Server) pathCrawler(request PathRequest, extraOptions ...crawling.Option) (crawler *crawling.WikiCrawler, err error) {
+	if request.Start == "" || request.Target == "" {
+		return nil, fmt.Errorf("start and target are required")
+	}
+
+	maxHops := server.config.MaxHops
+	if request.MaxHops > maxHops {
+		return nil, fmt.Errorf("max_hops must not exceed %d", maxHops)
+	}
+	if request.MaxHops > 0 {
+		maxHops = request.MaxHops
+	}
+
+	var defaultWiki string
+	if defaultWiki, err = server.defaultWiki(request.Wiki, request.Lang); err != nil {
+		return
+	}
+
+	resolve := crawling.ResolvePages
+	if request.Interlanguage {
+		resolve = crawling.ResolvePagesAcrossWikis
+	}
+
+	var start, target crawling.PageReference
+	if start, target, err = resolve(request.Start, request.Target, defaultWiki, server.config.SiteProfiles); err != nil {
+		return
+	}
+
+	explicitWiki := request.Wiki != "" || request.Lang != ""
+	if explicitWiki && !crawling.SameWiki(start.WikiBase, defaultWiki) {
+		return nil, fmt.Errorf("pages belong to %s but %s was selected by wiki or lang", start.WikiBase, defaultWiki)
+	}
+
+	options := []crawling.Option{
+		crawling.WithMaxHops(maxHops),
+		crawling.WithFrontierSpilling(server.config.SpillDirectory, server.config.FrontierMemoryLimit),
+	}
+	if request.LinkContext {
+		options = append(options, crawling.WithLinkContext())
+	}
+	if request.Interlanguage {
+		options = append(options, crawling.WithInterlanguageLinks())
+	}
+	if server.metrics != nil {
+		options = append(options, crawling.WithObserver(server.metrics))
+	}
+	if server.tracer != nil {
+		options = append(options, crawling.WithTracer(server.tracer))
+	}
+	crawler = server.engine.NewSearch(start, target, append(options, extraOptions...)...)
+	return
+}
@@ -0,0 +1,152 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/baez90/shortest-path/internal/app/jobs"
+	"github.com/baez90/shortest-path/internal/app/metrics"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+)
+
+// Config are the settings applying to all requests, requests may only select another wiki or fewer hops
+type Config struct {
+	// DefaultWiki is the base URL of the wiki titles are resolved in if a request selects no wiki
+	DefaultWiki  string
+	SiteProfiles crawling.SiteProfiles
+	// MaxHops is the default and the upper limit of the depth of every search
+	MaxHops             uint16
+	SpillDirectory      string
+	FrontierMemoryLimit uint64
+	// SearchTimeout limits the duration of every search, 0 disables the limit
+	SearchTimeout time.Duration
+	// LinkCacheSize is the number of bytes of links to share between all searches, 0 disables sharing links
+	LinkCacheSize uint64
+}
+
+// Server answers path and link queries via a JSON API.
+// Every query runs its own crawler, the links of processed pages are shared between searches by the engine
+// and the fetched pages between all queries via the page cache.
+type Server struct {
+	config Config
+	cache  *crawling.PageCache
+	engine *crawling.Engine
+	mux    *http.ServeMux
+	// jobs runs the searches submitted as jobs, nil if jobs are disabled
+	jobs *jobs.Manager
+	// metrics observes every search, nil if metrics are disabled
+	metrics *metrics.Metrics
+	// tracer records the spans of every search, nil if tracing is disabled
+	tracer crawling.Tracer
+}
+
+// queryError is an error of a query carrying the HTTP status describing it
+type queryError struct {
+	status int
+	err    error
+}
+
+func (e *queryError) Error() string {
+	return e.err.Error()
+}
+
+func (e *queryError) Unwrap() error {
+	return e.err
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewServer creates a server retrieving all pages via the given cache
+func NewServer(config Config, cache *crawling.PageCache) *Server {
+	server := &Server{
+		config: config,
+		cache:  cache,
+		engine: crawling.NewEngine(config.LinkCacheSize, crawling.WithPageCache(cache)),
+		mux:    http.NewServeMux(),
+	}
+	server.mux.HandleFunc("/v1/paths", server.handlePaths)
+	server.mux.HandleFunc(linksPathPrefix, server.handleLinks)
+	return server
+}
+
+// EnableMetrics lets every search be observed by the given metrics and exposes them on GET /metrics
+// together with the statistics of the link cache
+func (server *Server) EnableMetrics(m *metrics.Metrics) {
+	server.metrics = m
+	m.RegisterLinkCache(server.engine.LinkCache())
+	server.mux.Handle("/metrics", m.Handler())
+}
+
+// EnableTracing lets every search record its spans with the given tracer
+func (server *Server) EnableTracing(tracer crawling.Tracer) {
+	server.tracer = tracer
+}
+
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server.mux.ServeHTTP(w, r)
+}
+
+// defaultWiki returns the wiki selected by the request or the default wiki of the server
+func (server *Server) defaultWiki(wiki, lang string) (string, error) {
+	switch {
+	case wiki != "":
+		return crawling.ParseWikiBaseURL(wiki)
+	case lang != "":
+		return crawling.WikipediaBaseURL(lang)
+	default:
+		return server.config.DefaultWiki, nil
+	}
+}
+
+// allowMethod rejects requests with any other than the given method
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed, use %s", r.Method, method))
+	return false
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// writeQueryError responds with the status of a queryError, with 500 for any other error
+func writeQueryError(w http.ResponseWriter, err error) {
+	var queryErr *queryError
+	if errors.As(err, &queryErr) {
+		writeError(w, queryErr.status, queryErr.err)
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.
+			WithError(err).
+			Warn("Failed to write response")
+	}
+}
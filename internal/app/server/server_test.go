@@ -0,0 +1,431 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/baez90/shortest-path/internal/app/jobs"
+	"github.com/baez90/shortest-path/internal/app/metrics"
+	"github.com/baez90/shortest-path/internal/app/output"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	"github.com/baez90/shortest-path/pkg/crawling/crawlingtest"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestWiki links every page to the pages listed for it, all other pages do not exist
+func newTestWiki() *crawlingtest.Wiki {
+	return crawlingtest.NewWiki(crawlingtest.Pages(map[string][]string{
+		"Alpha":    {"Beta", "File:Alpha.svg"},
+		"Beta":     {"Gamma", "Alpha"},
+		"Gamma":    {"Delta"},
+		"Delta":    {},
+		"Isolated": {},
+	}))
+}
+
+func newTestServer(wiki *crawlingtest.Wiki) *Server {
+	return NewServer(Config{
+		DefaultWiki:  "https://en.wikipedia.org",
+		SiteProfiles: crawling.BuiltinSiteProfiles(),
+		MaxHops:      5,
+	}, crawling.NewPageCache(wiki.Fetch, 1<<20))
+}
+
+func TestServer_handlePaths(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+		wantFound  bool
+		wantHops   int
+	}{
+		{
+			name:       "find path between titles",
+			method:     http.MethodPost,
+			body:       `{"start": "Alpha", "target": "Delta"}`,
+			wantStatus: http.StatusOK,
+			wantFound:  true,
+			wantHops:   3,
+		},
+		{
+			name:       "report target out of reach as not found",
+			method:     http.MethodPost,
+			body:       `{"start": "Alpha", "target": "Delta", "max_hops": 2}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "reject more hops than allowed by the server",
+			method:     http.MethodPost,
+			body:       `{"start": "Alpha", "target": "Delta", "max_hops": 6}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "reject unknown options",
+			method:     http.MethodPost,
+			body:       `{"start": "Alpha", "target": "Delta", "depth": 3}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "reject missing target",
+			method:     http.MethodPost,
+			body:       `{"start": "Alpha"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "reject pages of another wiki than selected",
+			method:     http.MethodPost,
+			body:       `{"start": "https://en.wikipedia.org/wiki/Alpha", "target": "Delta", "lang": "de"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "reject other methods",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(tt.method, "/v1/paths", strings.NewReader(tt.body))
+			newTestServer(newTestWiki()).ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var result output.SearchResult
+			if err := json.NewDecoder(recorder.Body).Decode(&result); err != nil {
+				t.Fatal(err)
+			}
+			if result.Found != tt.wantFound || result.Hops != tt.wantHops {
+				t.Errorf("result found = %v with %d hops, want %v with %d hops", result.Found, result.Hops, tt.wantFound, tt.wantHops)
+			}
+		})
+	}
+}
+
+func TestServer_handlePaths_SharedCache(t *testing.T) {
+	wiki := newTestWiki()
+	server := newTestServer(wiki)
+
+	for _, target := range []string{"Gamma", "Delta"} {
+		recorder := httptest.NewRecorder()
+		body := fmt.Sprintf(`{"start": "Alpha", "target": "%s"}`, target)
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/v1/paths", strings.NewReader(body)))
+
+		var result output.SearchResult
+		if err := json.NewDecoder(recorder.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+		// every search starts with an empty set of visited pages even though the pages are cached
+		if !result.Found || result.FetchedPages == 0 {
+			t.Errorf("search for %s: found = %v, fetched %d pages", target, result.Found, result.FetchedPages)
+		}
+	}
+
+	for title, fetches := range wiki.Fetches() {
+		if fetches != 1 {
+			t.Errorf("retrieved %s %d times, want once", title, fetches)
+		}
+	}
+}
+
+func TestServer_handlePaths_Interrupted(t *testing.T) {
+	tests := []struct {
+		name       string
+		timeout    time.Duration
+		cancel     bool
+		wantStatus int
+		wantBody   bool
+	}{
+		{
+			name:       "respond with timeout if the search times out",
+			timeout:    10 * time.Millisecond,
+			wantStatus: http.StatusGatewayTimeout,
+			wantBody:   true,
+		},
+		{
+			name:   "respond with nothing if the client went away",
+			cancel: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wiki := newTestWiki()
+			wiki.Blocking = "Alpha"
+			server := newTestServer(wiki)
+			server.config.SearchTimeout = tt.timeout
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/v1/paths", strings.NewReader(`{"start": "Alpha", "target": "Delta"}`))
+			server.ServeHTTP(recorder, request.WithContext(ctx))
+
+			if gotBody := recorder.Body.Len() > 0; gotBody != tt.wantBody {
+				t.Fatalf("responded with body = %v, want %v: %s", gotBody, tt.wantBody, recorder.Body.String())
+			}
+			if tt.wantBody && recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestServer_metrics(t *testing.T) {
+	wiki := newTestWiki()
+	cache := crawling.NewPageCache(wiki.Fetch, 1<<20)
+	server := NewServer(Config{
+		DefaultWiki:   "https://en.wikipedia.org",
+		SiteProfiles:  crawling.BuiltinSiteProfiles(),
+		MaxHops:       5,
+		LinkCacheSize: 1 << 20,
+	}, cache)
+	m := metrics.New()
+	m.RegisterPageCache(cache)
+	server.EnableMetrics(m)
+
+	for i := 0; i < 2; i++ {
+		server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v1/paths", strings.NewReader(`{"start": "Alpha", "target": "Delta"}`)))
+	}
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusOK)
+	}
+	for _, want := range []string{
+		`shortest_path_searches_total{outcome="found"} 2`,
+		// the second search takes all links from the link cache without fetching any page
+		"shortest_path_fetched_pages_total 3",
+		"shortest_path_cached_pages_total 3",
+		"shortest_path_link_cache_hits_total 3",
+		"shortest_path_link_cache_misses_total 3",
+		"shortest_path_page_cache_hits_total 0",
+		"shortest_path_page_cache_misses_total 3",
+	} {
+		if !strings.Contains(recorder.Body.String(), want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, recorder.Body.String())
+		}
+	}
+}
+
+func TestServer_handleLinks(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantTitles []string
+	}{
+		{
+			name:       "list all links",
+			target:     "/v1/links/Alpha",
+			wantStatus: http.StatusOK,
+			wantTitles: []string{"Beta", ""},
+		},
+		{
+			name:       "list kept links only",
+			target:     "/v1/links/Alpha?kept_only=true",
+			wantStatus: http.StatusOK,
+			wantTitles: []string{"Beta"},
+		},
+		{
+			name:       "reject invalid flag",
+			target:     "/v1/links/Alpha?kept_only=maybe",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "reject missing title",
+			target:     "/v1/links/",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "report unavailable page",
+			target:     "/v1/links/Omega",
+			wantStatus: http.StatusBadGateway,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			newTestServer(newTestWiki()).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var reports []output.LinkReport
+			if err := json.NewDecoder(recorder.Body).Decode(&reports); err != nil {
+				t.Fatal(err)
+			}
+			if len(reports) != len(tt.wantTitles) {
+				t.Fatalf("got %d links, want %d", len(reports), len(tt.wantTitles))
+			}
+			for idx, report := range reports {
+				if report.Title != tt.wantTitles[idx] {
+					t.Errorf("link %d = %q, want %q", idx, report.Title, tt.wantTitles[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestServer_jobs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "shortest-path-server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := jobs.OpenStore(filepath.Join(dir, "jobs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	manager, err := jobs.NewManager(store, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Close()
+
+	server := newTestServer(newTestWiki())
+	server.EnableJobs(manager)
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/v1/jobs", strings.NewReader(`{"start": "Alpha", "target": "Delta"}`)))
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf("submit status = %d: %s", recorder.Code, recorder.Body.String())
+	}
+	location := recorder.Header().Get("Location")
+
+	var job jobs.Job
+	for deadline := time.Now().Add(5 * time.Second); job.FinishedAt == nil && time.Now().Before(deadline); {
+		recorder = httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, location, nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("poll status = %d: %s", recorder.Code, recorder.Body.String())
+		}
+		if err := json.NewDecoder(recorder.Body).Decode(&job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if job.State != jobs.StateDone || job.Result == nil || job.Result.Hops != 3 {
+		t.Errorf("job = %+v, want done with 3 hops", job)
+	}
+
+	for _, method := range []string{http.MethodDelete, http.MethodGet} {
+		recorder = httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(method, location, nil))
+		wantStatus := http.StatusOK
+		if method == http.MethodGet {
+			wantStatus = http.StatusNotFound
+		}
+		if recorder.Code != wantStatus {
+			t.Errorf("%s status = %d, want %d", method, recorder.Code, wantStatus)
+		}
+	}
+}
+
+func TestServer_handleJobEvents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "shortest-path-server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := jobs.OpenStore(filepath.Join(dir, "jobs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	manager, err := jobs.NewManager(store, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Close()
+
+	wiki := newTestWiki()
+	wiki.Gate = make(chan struct{})
+	server := newTestServer(wiki)
+	server.EnableJobs(manager)
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	resp, err := http.Post(httpServer.URL+"/v1/jobs", "application/json", strings.NewReader(`{"start": "Alpha", "target": "Delta"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	stream, err := http.Get(httpServer.URL + resp.Header.Get("Location") + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Body.Close()
+	if contentType := stream.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("Content-Type = %s", contentType)
+	}
+	close(wiki.Gate)
+
+	var names []string
+	var finished jobs.Job
+	scanner := bufio.NewScanner(stream.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			names = append(names, strings.TrimPrefix(line, "event: "))
+		case strings.HasPrefix(line, "data: ") && names[len(names)-1] == "finished":
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &finished); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// the first level might be started before the stream was subscribed, the job event reflects the state at that point
+	wantNames := []string{
+		"page_processed", "level_finished",
+		"level_started", "level_finished",
+		"level_started", "path_found",
+		"finished",
+	}
+	if len(names) < len(wantNames)+1 || names[0] != "job" || !reflect.DeepEqual(names[len(names)-len(wantNames):], wantNames) {
+		t.Errorf("events = %v, want job followed by %v", names, wantNames)
+	}
+	if finished.State != jobs.StateDone || finished.Result == nil || !finished.Result.Found {
+		t.Errorf("finished job = %+v", finished)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing exports OpenTelemetry spans of searches via OTLP or to a local JSON file.
+package tracing
+
+import (
+	"context"
+	"github.com/baez90/shortest-path/pkg/crawling"
+	"go.opentelemetry.io/otel/api/kv"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/trace/stdout"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/codes"
+	"io"
+	"os"
+	"sync"
+)
+
+const (
+	serviceName = "shortest-path"
+	tracerName  = "github.com/baez90/shortest-path"
+)
+
+// Config selects the exporters of the spans, tracing is disabled if no exporter is selected
+type Config struct {
+	// OTLPEndpoint is the address of a collector receiving spans via OTLP over gRPC e.g. localhost:55680
+	OTLPEndpoint string
+	// File receives every span as JSON object on a line of its own, spans are appended to existing files
+	File string
+}
+
+// Enabled is true if any exporter is selected
+func (config Config) Enabled() bool {
+	return config.OTLPEndpoint != "" || config.File != ""
+}
+
+// Tracing records all spans and exports them until it is closed
+type Tracing struct {
+	provider     *sdktrace.Provider
+	file         *os.File
+	otlpExporter *otlp.Exporter
+	otlpBatcher  *sdktrace.BatchSpanProcessor
+}
+
+// Open sets up the exporters selected by the config, all spans are sampled
+func Open(config Config) (tracing *Tracing, err error) {
+	tracing = &Tracing{}
+	if tracing.provider, err = sdktrace.NewProvider(
+		sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.AlwaysSample()}),
+		sdktrace.WithResource(resource.New(kv.String("service.name", serviceName))),
+	); err != nil {
+		return nil, err
+	}
+
+	if config.File != "" {
+		if tracing.file, err = os.OpenFile(config.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644); err != nil {
+			return nil, err
+		}
+		var exporter *stdout.Exporter
+		if exporter, err = stdout.NewExporter(stdout.Options{Writer: &lockedWriter{w: tracing.file}}); err != nil {
+			tracing.Close()
+			return nil, err
+		}
+		// spans are written synchronously so that no span is lost when the process exits
+		tracing.provider.RegisterSpanProcessor(sdktrace.NewSimpleSpanProcessor(exporter))
+	}
+
+	if config.OTLPEndpoint != "" {
+		if tracing.otlpExporter, err = otlp.NewExporter(otlp.WithInsecure(), otlp.WithAddress(config.OTLPEndpoint)); err != nil {
+			tracing.Close()
+			return nil, err
+		}
+		if tracing.otlpBatcher, err = sdktrace.NewBatchSpanProcessor(tracing.otlpExporter); err != nil {
+			tracing.Close()
+			return nil, err
+		}
+		tracing.provider.RegisterSpanProcessor(tracing.otlpBatcher)
+	}
+	return
+}
+
+// Tracer returns the tracer to record the spans of searches with e.g. by crawling.WithTracer
+func (tracing *Tracing) Tracer() crawling.Tracer {
+	return otelTracer{tracer: tracing.provider.Tracer(tracerName)}
+}
+
+// otelTracer records the spans of the crawler as OpenTelemetry spans
+type otelTracer struct {
+	tracer trace.Tracer
+}
+
+func (tracer otelTracer) Start(ctx context.Context, name string, attributes ...crawling.SpanAttribute) (context.Context, crawling.Span) {
+	ctx, span := tracer.tracer.Start(ctx, name, trace.WithAttributes(otelAttributes(attributes)...))
+	return ctx, otelSpan{ctx: ctx, span: span}
+}
+
+type otelSpan struct {
+	ctx  context.Context
+	span trace.Span
+}
+
+func (span otelSpan) SetAttributes(attributes ...crawling.SpanAttribute) {
+	span.span.SetAttributes(otelAttributes(attributes)...)
+}
+
+// End marks the span as failed with the error if any before it is ended
+func (span otelSpan) End(err error) {
+	if err != nil {
+		span.span.RecordError(span.ctx, err, trace.WithErrorStatus(codes.Unknown))
+	}
+	span.span.End()
+}
+
+func otelAttributes(attributes []crawling.SpanAttribute) (converted []kv.KeyValue) {
+	converted = make([]kv.KeyValue, 0, len(attributes))
+	for _, attribute := range attributes {
+		converted = append(converted, kv.Infer(attribute.Key, attribute.Value))
+	}
+	return
+}
+
+// Close exports all pending spans and releases the exporters
+func (tracing *Tracing) Close() (err error) {
+	if tracing.otlpBatcher != nil {
+		tracing.provider.UnregisterSpanProcessor(tracing.otlpBatcher)
+	}
+	if tracing.otlpExporter != nil {
+		err = tracing.otlpExporter.Stop()
+	}
+	if tracing.file != nil {
+		if closeErr := tracing.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return
+}
+
+// lockedWriter serializes the spans ended concurrently by multiple searches
+type lockedWriter struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+func (writer *lockedWriter) Write(p []byte) (int, error) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	return writer.w.Write(p)
+}
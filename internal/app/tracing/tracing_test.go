@@ -0,0 +1,96 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTracing_File(t *testing.T) {
+	dir, err := ioutil.TempDir("", "shortest-path-tracing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "spans.jsonl")
+
+	// spans of subsequent runs are appended
+	for run := 0; run < 2; run++ {
+		tracing, err := Open(Config{File: path})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, parent := tracing.Tracer().Start(context.Background(), "SearchShortestPath")
+		_, child := tracing.Tracer().Start(ctx, "level")
+		child.End(nil)
+		parent.End(nil)
+		if err = tracing.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var names []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var span struct {
+			Name string
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &span); err != nil {
+			t.Fatalf("span %q is no JSON object: %v", scanner.Text(), err)
+		}
+		names = append(names, span.Name)
+	}
+
+	wantNames := []string{"level", "SearchShortestPath", "level", "SearchShortestPath"}
+	if len(names) != len(wantNames) {
+		t.Fatalf("spans = %v, want %v", names, wantNames)
+	}
+	for idx := range names {
+		if names[idx] != wantNames[idx] {
+			t.Errorf("span %d = %s, want %s", idx, names[idx], wantNames[idx])
+		}
+	}
+}
+
+func TestConfig_Enabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   bool
+	}{
+		{name: "no exporter", config: Config{}, want: false},
+		{name: "file", config: Config{File: "spans.jsonl"}, want: true},
+		{name: "OTLP", config: Config{OTLPEndpoint: "localhost:55680"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.Enabled(); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
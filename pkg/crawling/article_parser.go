@@ -0,0 +1,136 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/html"
+	"io"
+)
+
+var (
+	// errUnmatchedEndTag fails the parsing of a page whose content root closes an element other than the latest opened one
+	errUnmatchedEndTag = errors.New("latest token on stack does not match closing tag")
+)
+
+// extractLinksFromContent collects all article links in the content root of the given page as defined by the site profile.
+// If the scope enables interlanguage links the language list following the content root is collected as well.
+// Links to titles which are already known to the interner are skipped, the returned links are new pages only.
+// If linkContexts is not nil the context of every returned article link is stored in it.
+func extractLinksFromContent(body io.Reader, scope linkScope, titles *titleInterner, linkContexts map[pageID]LinkContext) (links []pageLink, err error) {
+	tokenStack := tokenStack{}
+	var collector *linkContextCollector
+	if linkContexts != nil {
+		collector = newLinkContextCollector(linkContexts)
+		defer collector.finish()
+	}
+	tokenizer := html.NewTokenizer(body)
+
+	var token html.Token
+	site := scope.site
+	token, err = seekDOMElementBySelector(tokenizer, site.ContentRoot.Element, site.ContentRoot.Attribute, site.ContentRoot.Value)
+
+	if err != nil {
+		return
+	}
+
+	tokenStack.Push(token)
+
+	for !tokenStack.Empty() && tokenizer.Next() != html.ErrorToken {
+		currentToken := tokenizer.Token()
+		switch currentToken.Type {
+		case html.EndTagToken:
+			latestToken, ok := tokenStack.Peek()
+			if !ok {
+				err = fmt.Errorf("token stack already empty")
+				return
+			}
+			if latestToken.Data != currentToken.Data {
+				err = errUnmatchedEndTag
+				return
+			} else {
+				if collector != nil {
+					collector.endTag(currentToken, len(tokenStack.tokens))
+				}
+				tokenStack.Pop()
+			}
+			break
+		case html.TextToken:
+			if collector != nil {
+				collector.text(currentToken.Data)
+			}
+			break
+		case html.StartTagToken, html.SelfClosingTagToken:
+
+			// push tag to stack to be able to track closing tags
+			if currentToken.Type == html.StartTagToken {
+				tokenStack.Push(currentToken)
+				if collector != nil {
+					collector.startTag(currentToken, len(tokenStack.tokens))
+				}
+			}
+			if currentToken.Data == "a" {
+				for _, attr := range currentToken.Attr {
+					if attr.Key != "href" {
+						continue
+					}
+					title, isArticle := site.articleTitle(attr.Val)
+					if !isArticle {
+						continue
+					}
+					if id, alreadyPresent := titles.Intern(scope.key(scope.wiki, title)); !alreadyPresent {
+						log.Debugf("Enqueuing discovered link %s", attr.Val)
+						links = append(links, pageLink{id: id, kind: ArticleLink})
+						if collector != nil {
+							collector.link(id)
+						}
+					}
+				}
+			}
+			break
+		}
+	}
+
+	if scope.interlanguage && tokenStack.Empty() {
+		links = append(links, extractInterlanguageLinks(tokenizer, scope, titles)...)
+	}
+	return
+}
+
+func seekDOMElementBySelector(tokenizer *html.Tokenizer, elementType, selectorKey, selectorValue string) (token html.Token, err error) {
+	for tokenizer.Next() != html.ErrorToken {
+		switch token = tokenizer.Token(); token.Type {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if token.Data == elementType {
+				for _, attr := range token.Attr {
+					if attr.Key == selectorKey && attr.Val == selectorValue {
+						return
+					}
+				}
+				if selectorKey == "class" && hasClass(token, selectorValue) {
+					return
+				}
+			}
+		}
+	}
+	err = elementNotFoundError(elementType, selectorKey, selectorValue)
+	return
+}
+
+func elementNotFoundError(elementType, selectorKey, selectorValue string) error {
+	return fmt.Errorf("requested element with type %s and selector %s=%s not found", elementType, selectorKey, selectorValue)
+}
@@ -25,9 +25,10 @@ import (
 
 func Test_extractLinksFromContent(t *testing.T) {
 	type args struct {
-		body         io.ReadCloser
-		visitedPages *stringSet
-		formatter    linkFormatter
+		body          io.ReadCloser
+		titles        *titleInterner
+		linkContexts  map[pageID]LinkContext
+		interlanguage bool
 	}
 	tests := []struct {
 		name            string
@@ -38,31 +39,45 @@ func Test_extractLinksFromContent(t *testing.T) {
 		{
 			name: "Get links from Manduca Jordani article",
 			args: args{
-				body:         MustOpen("../../../assets/test-data/manduca_jordani_article.html"),
-				visitedPages: newStringSet(),
-				formatter: func(s string) string {
-					return s
-				},
+				body:   MustOpen("../../assets/test-data/manduca_jordani_article.html"),
+				titles: newTitleInterner(),
 			},
 			wantNumberLinks: 18,
 			wantErr:         false,
 		},
+		{
+			name: "Get links and their context from Times New Roman article",
+			args: args{
+				body:         MustOpen("../../assets/test-data/times_new_roman_article.html"),
+				titles:       newTitleInterner(),
+				linkContexts: make(map[pageID]LinkContext),
+			},
+			wantNumberLinks: 344,
+			wantErr:         false,
+		},
+		{
+			name: "Get links and interlanguage links from Manduca Jordani article",
+			args: args{
+				body:          MustOpen("../../assets/test-data/manduca_jordani_article.html"),
+				titles:        newTitleInterner(),
+				interlanguage: true,
+			},
+			wantNumberLinks: 18 + 4,
+			wantErr:         false,
+		},
 		{
 			name: "Get links from Times New Roman article",
 			args: args{
-				body:         MustOpen("../../../assets/test-data/times_new_roman_article.html"),
-				visitedPages: newStringSet(),
-				formatter: func(s string) string {
-					return s
-				},
+				body:   MustOpen("../../assets/test-data/times_new_roman_article.html"),
+				titles: newTitleInterner(),
 			},
-			wantNumberLinks: 334,
+			wantNumberLinks: 344,
 			wantErr:         false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotLinks, err := extractLinksFromContent(tt.args.body, tt.args.visitedPages, tt.args.formatter)
+			gotLinks, err := extractLinksFromContent(tt.args.body, linkScope{site: builtinSiteProfiles[0], interlanguage: tt.args.interlanguage}, tt.args.titles, tt.args.linkContexts)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("extractLinksFromContent() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -71,6 +86,14 @@ func Test_extractLinksFromContent(t *testing.T) {
 			if len(gotLinks) != tt.wantNumberLinks {
 				t.Errorf("extractLinksFromContent() gotLinks = %v, want number of links %d", gotLinks, tt.wantNumberLinks)
 			}
+
+			if tt.args.titles.Len() != tt.wantNumberLinks {
+				t.Errorf("Expected %d interned titles but got %d", tt.wantNumberLinks, tt.args.titles.Len())
+			}
+
+			if tt.args.linkContexts != nil && len(tt.args.linkContexts) != tt.wantNumberLinks {
+				t.Errorf("Expected %d link contexts but got %d", tt.wantNumberLinks, len(tt.args.linkContexts))
+			}
 		})
 	}
 }
@@ -133,7 +156,7 @@ func Test_seekDOMElementBySelector(t *testing.T) {
 		},
 	}
 	for _, tt := range tests {
-		f, _ := os.Open("../../../assets/test-data/times_new_roman_article.html")
+		f, _ := os.Open("../../assets/test-data/times_new_roman_article.html")
 
 		tokenizer := html.NewTokenizer(f)
 
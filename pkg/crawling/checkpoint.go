@@ -0,0 +1,206 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"bufio"
+	"encoding/gob"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	// checkpointVersion is incremented whenever the format or the meaning of the persisted progress changes,
+	// checkpoints of other versions are rejected
+	checkpointVersion = 3
+)
+
+// checkpoint is the persisted progress of a search.
+// It is the gob encoded header of a checkpoint file, the header is followed by the page IDs of the current and the next frontier
+// in the binary format of frontier segments to stream spilled frontiers without loading them into memory.
+// The frontiers are stored completely, CurrentProcessed tells how many pages of the current level are already done.
+type checkpoint struct {
+	Version            int
+	StartTitle         string
+	TargetTitle        string
+	WikiBaseDomain     string
+	Site               SiteProfile
+	Depth              uint16
+	FetchedPages       uint
+	CachedPages        uint
+	Titles             []string
+	Parents            []pageID
+	CurrentFrontierLen uint64
+	CurrentProcessed   uint64
+	NextFrontierLen    uint64
+	LinkContexts       map[pageID]LinkContext
+	InterlanguageEdges map[pageID]bool
+}
+
+// ResumeWikiCrawler restores a crawler from the given checkpoint and configures it with the given options.
+// The next call to SearchShortestPath continues the search where the checkpoint was taken.
+func ResumeWikiCrawler(checkpointPath string, options ...Option) (crawler *WikiCrawler, err error) {
+	var file *os.File
+	if file, err = os.Open(checkpointPath); err != nil {
+		return
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var cp *checkpoint
+	if cp, err = readCheckpoint(checkpointPath, reader); err != nil {
+		return
+	}
+
+	crawler = NewWikiCrawlerForPages(
+		PageReference{WikiBase: cp.WikiBaseDomain, Title: cp.StartTitle, Site: cp.Site},
+		PageReference{WikiBase: cp.WikiBaseDomain, Title: cp.TargetTitle, Site: cp.Site},
+	)
+	crawler.fetchedPages = cp.FetchedPages
+	crawler.cachedPages = cp.CachedPages
+	for _, title := range cp.Titles {
+		crawler.titles.Intern(title)
+	}
+	crawler.parents.parents = cp.Parents
+	crawler.linkContexts = cp.LinkContexts
+	crawler.interlanguageEdges = cp.InterlanguageEdges
+	for _, option := range options {
+		option(crawler)
+	}
+
+	// the frontiers are restored after the options are applied to spill them as configured
+	if crawler.resumeState, err = cp.restoreSearchState(reader, crawler.newFrontier); err != nil {
+		return nil, fmt.Errorf("%w %s: %v", ErrInvalidCheckpoint, checkpointPath, err)
+	}
+	return
+}
+
+func (crawler *WikiCrawler) checkpointIfDue(state *searchState) {
+	if crawler.checkpointPath == "" || time.Since(crawler.lastCheckpoint) < crawler.checkpointInterval {
+		return
+	}
+
+	if err := crawler.writeCheckpoint(state); err != nil {
+		log.WithError(err).Warnf("Failed to write checkpoint to %s", crawler.checkpointPath)
+	}
+}
+
+// writeCheckpoint persists the given search state, the frontiers are streamed into the checkpoint.
+// The checkpoint is written to a temporary file first and renamed afterwards to never leave a partial checkpoint behind.
+func (crawler *WikiCrawler) writeCheckpoint(state *searchState) (err error) {
+	cp := checkpoint{
+		Version:            checkpointVersion,
+		StartTitle:         crawler.startTitle,
+		TargetTitle:        crawler.targetTitle,
+		WikiBaseDomain:     crawler.wikiBaseDomain,
+		Site:               crawler.site,
+		Depth:              state.depth,
+		FetchedPages:       crawler.fetchedPages,
+		CachedPages:        crawler.cachedPages,
+		Titles:             crawler.titles.titles,
+		Parents:            crawler.parents.parents,
+		CurrentFrontierLen: state.current.Len(),
+		CurrentProcessed:   state.processed,
+		NextFrontierLen:    state.next.Len(),
+		LinkContexts:       crawler.linkContexts,
+		InterlanguageEdges: crawler.interlanguageEdges,
+	}
+
+	tmpPath := crawler.checkpointPath + ".tmp"
+	var file *os.File
+	if file, err = os.Create(tmpPath); err != nil {
+		return
+	}
+
+	writer := bufio.NewWriter(file)
+	if err = gob.NewEncoder(writer).Encode(cp); err == nil {
+		err = writePageIDs(writer, state.current.Iterate)
+	}
+	if err == nil {
+		err = writePageIDs(writer, state.next.Iterate)
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return
+	}
+
+	if err = os.Rename(tmpPath, crawler.checkpointPath); err != nil {
+		return
+	}
+
+	crawler.lastCheckpoint = time.Now()
+	log.WithFields(log.Fields{
+		"depth":     state.depth,
+		"processed": state.processed,
+	}).Debugf("Wrote checkpoint to %s", crawler.checkpointPath)
+	return
+}
+
+// readCheckpoint decodes the header of the checkpoint, the reader is left at the frontiers following the header.
+// The reader has to be buffered already as the decoder would otherwise buffer parts of the frontiers itself.
+func readCheckpoint(path string, reader *bufio.Reader) (cp *checkpoint, err error) {
+	cp = &checkpoint{}
+	if err = gob.NewDecoder(reader).Decode(cp); err != nil {
+		err = fmt.Errorf("%w %s: %v", ErrInvalidCheckpoint, path, err)
+		return
+	}
+
+	if cp.Version != checkpointVersion {
+		err = fmt.Errorf("%w %s: unsupported version %d", ErrInvalidCheckpoint, path, cp.Version)
+	}
+	return
+}
+
+// restoreSearchState streams the frontiers following the header of the checkpoint into new frontiers
+func (cp checkpoint) restoreSearchState(reader io.Reader, newFrontier func() frontier) (state *searchState, err error) {
+	state = &searchState{
+		depth:     cp.Depth,
+		current:   newFrontier(),
+		next:      newFrontier(),
+		processed: cp.CurrentProcessed,
+	}
+
+	if err = restoreFrontier(reader, state.current, cp.CurrentFrontierLen); err == nil {
+		err = restoreFrontier(reader, state.next, cp.NextFrontierLen)
+	}
+
+	if err != nil {
+		state.Close()
+		state = nil
+	}
+	return
+}
+
+// restoreFrontier pushes the next length page IDs of the reader to the frontier
+func restoreFrontier(reader io.Reader, f frontier, length uint64) (err error) {
+	if err = readPageIDs(io.LimitReader(reader, int64(length*pageIDSize)), f.Push); err != nil {
+		return
+	}
+	if f.Len() != length {
+		err = fmt.Errorf("frontier truncated after %d of %d pages", f.Len(), length)
+	}
+	return
+}
@@ -0,0 +1,191 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"github.com/baez90/shortest-path/pkg/crawling/crawlingtest"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResumeWikiCrawler(t *testing.T) {
+	type args struct {
+		graph          crawlingtest.Tree
+		target         int
+		maxHops        uint16
+		interruptAfter uint
+		memoryLimit    uint64
+	}
+	tests := []struct {
+		name string
+		args args
+	}{
+		{
+			name: "Resume within the first level",
+			args: args{
+				graph:          crawlingtest.Tree{Branching: 3, Nodes: 400},
+				target:         364,
+				maxHops:        6,
+				interruptAfter: 1,
+			},
+		},
+		{
+			name: "Resume within a deeper level",
+			args: args{
+				graph:          crawlingtest.Tree{Branching: 3, Nodes: 400},
+				target:         364,
+				maxHops:        6,
+				interruptAfter: 30,
+			},
+		},
+		{
+			name: "Resume with spilled frontier",
+			args: args{
+				graph:          crawlingtest.Tree{Branching: 3, Nodes: 400},
+				target:         364,
+				maxHops:        6,
+				interruptAfter: 50,
+				memoryLimit:    8 * pageIDSize,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkpointDirectory, err := ioutil.TempDir("", "checkpoint-test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(checkpointDirectory)
+
+			checkpointPath := filepath.Join(checkpointDirectory, "search.checkpoint")
+			snapshotPath := filepath.Join(checkpointDirectory, "snapshot.checkpoint")
+
+			reference := treeCrawler(tt.args.graph, tt.args.target, tt.args.maxHops)
+			wantResult, err := reference.SearchShortestPath(context.Background())
+			if err != nil {
+				t.Fatalf("SearchShortestPath() error = %v", err)
+			}
+
+			// keep the checkpoint written after interruptAfter pages as if the search was aborted there
+			interrupted := treeCrawler(tt.args.graph, tt.args.target, tt.args.maxHops,
+				WithFrontierSpilling(checkpointDirectory, tt.args.memoryLimit),
+				WithCheckpoints(checkpointPath, 0),
+			)
+			fetchPage := interrupted.fetchPage
+			interrupted.fetchPage = func(ctx context.Context, pageURI string) (io.ReadCloser, error) {
+				if interrupted.fetchedPages == tt.args.interruptAfter {
+					if err := copyFile(checkpointPath, snapshotPath); err != nil {
+						t.Fatal(err)
+					}
+				}
+				return fetchPage(ctx, pageURI)
+			}
+			if _, err := interrupted.SearchShortestPath(context.Background()); err != nil {
+				t.Fatalf("SearchShortestPath() error = %v", err)
+			}
+
+			resumed, err := ResumeWikiCrawler(snapshotPath, WithMaxHops(tt.args.maxHops), WithFrontierSpilling(checkpointDirectory, tt.args.memoryLimit))
+			if err != nil {
+				t.Fatalf("ResumeWikiCrawler() error = %v", err)
+			}
+			resumed.fetchPage = treeCrawler(tt.args.graph, tt.args.target, tt.args.maxHops).fetchPage
+
+			if resumed.StartPage() != reference.StartPage() || resumed.TargetPage() != reference.TargetPage() {
+				t.Errorf("Resumed crawler searches %s -> %s, want %s -> %s", resumed.StartPage(), resumed.TargetPage(), reference.StartPage(), reference.TargetPage())
+			}
+
+			gotResult, err := resumed.SearchShortestPath(context.Background())
+			if err != nil {
+				t.Fatalf("SearchShortestPath() after resume error = %v", err)
+			}
+
+			if !reflect.DeepEqual(gotResult.VisitedPages(), wantResult.VisitedPages()) {
+				t.Errorf("Resumed search path = %v, want %v", gotResult.VisitedPages(), wantResult.VisitedPages())
+			}
+
+			if resumed.FetchedPages() != reference.FetchedPages() {
+				t.Errorf("Resumed search fetched %d pages in total, want %d", resumed.FetchedPages(), reference.FetchedPages())
+			}
+
+			if resumed.DiscoveredPages() != reference.DiscoveredPages() {
+				t.Errorf("Resumed search discovered %d pages in total, want %d", resumed.DiscoveredPages(), reference.DiscoveredPages())
+			}
+		})
+	}
+}
+
+func TestResumeWikiCrawler_invalidCheckpoint(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(writer io.Writer) error
+	}{
+		{
+			name: "no checkpoint",
+			write: func(writer io.Writer) (err error) {
+				_, err = io.WriteString(writer, "definitely not a checkpoint")
+				return
+			},
+		},
+		{
+			name: "previous version",
+			write: func(writer io.Writer) error {
+				return gob.NewEncoder(writer).Encode(checkpoint{Version: checkpointVersion - 1})
+			},
+		},
+		{
+			name: "truncated frontier",
+			write: func(writer io.Writer) error {
+				if err := gob.NewEncoder(writer).Encode(checkpoint{Version: checkpointVersion, CurrentFrontierLen: 2}); err != nil {
+					return err
+				}
+				return writePageIDs(writer, func(fn func(id pageID) error) error {
+					return fn(0)
+				})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := ioutil.TempFile("", "checkpoint-test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.Remove(file.Name())
+			if err = tt.write(file); err != nil {
+				t.Fatal(err)
+			}
+			_ = file.Close()
+
+			if _, err := ResumeWikiCrawler(file.Name()); !errors.Is(err, ErrInvalidCheckpoint) {
+				t.Errorf("ResumeWikiCrawler() error = %v, want %v", err, ErrInvalidCheckpoint)
+			}
+		})
+	}
+}
+
+func copyFile(source, destination string) error {
+	content, err := ioutil.ReadFile(source)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(destination, content, 0600)
+}
@@ -0,0 +1,84 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawlingtest
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	treeTitlePrefix = "Node_"
+)
+
+// Tree describes a wiki whose articles form a complete tree with the given branching factor
+// where every article additionally links back to its parent. Node 0 is the root.
+type Tree struct {
+	Branching int
+	Nodes     int
+}
+
+// Children returns the nodes the node links to, its parent is the last of them
+func (tree Tree) Children(node int) (children []int) {
+	for child := node*tree.Branching + 1; child <= node*tree.Branching+tree.Branching && child < tree.Nodes; child++ {
+		children = append(children, child)
+	}
+	if node > 0 {
+		children = append(children, (node-1)/tree.Branching)
+	}
+	return
+}
+
+// Links is the LinkFunc of the tree to count its retrievals with NewWiki
+func (tree Tree) Links(title string) (links []string, ok bool) {
+	if !strings.HasPrefix(title, treeTitlePrefix) {
+		return nil, false
+	}
+	for _, child := range tree.Children(tree.Node(title)) {
+		links = append(links, tree.Title(child))
+	}
+	return links, true
+}
+
+// Fetch retrieves the article of the page URI without counting retrievals e.g. for benchmarks
+func (tree Tree) Fetch(_ context.Context, pageURI string) (io.ReadCloser, error) {
+	links, _ := tree.Links(Title(pageURI))
+	return ioutil.NopCloser(strings.NewReader(ArticleHTML(links...))), nil
+}
+
+// Title encodes the node index with letters only so that Node can decode it from the last title segment
+func (tree Tree) Title(node int) string {
+	encoded := []byte{byte('a' + node%26)}
+	for node /= 26; node > 0; node /= 26 {
+		encoded = append([]byte{byte('a' + node%26)}, encoded...)
+	}
+	return treeTitlePrefix + string(encoded)
+}
+
+// Node decodes the node index of a title or an article URI
+func (tree Tree) Node(pageURIOrTitle string) (node int) {
+	encoded := pageURIOrTitle[strings.LastIndex(pageURIOrTitle, "_")+1:]
+	for i := 0; i < len(encoded); i++ {
+		node = node*26 + int(encoded[i]-'a')
+	}
+	return
+}
+
+// PageURI returns the URI of the article of the node
+func (tree Tree) PageURI(node int) string {
+	return PageURI(tree.Title(node))
+}
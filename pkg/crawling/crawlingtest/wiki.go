@@ -0,0 +1,127 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package crawlingtest provides fake wikis serving articles from memory to test searches without network access.
+// It does not depend on the crawling package to be usable by its tests as well.
+package crawlingtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+)
+
+const (
+	// WikiBase is the base URL of all fake wikis, their articles are resolved with the builtin Wikipedia site profile
+	WikiBase = "https://en.wikipedia.org"
+)
+
+// LinkFunc returns the titles an article links to, ok is false if the wiki has no article with the given title
+type LinkFunc func(title string) (links []string, ok bool)
+
+// Wiki serves articles linking to the titles returned by its LinkFunc and counts the retrievals of every article.
+// It is safe for concurrent use by multiple searches.
+type Wiki struct {
+	links   LinkFunc
+	lock    sync.Mutex
+	fetches map[string]int
+	// Gate delays every retrieval until it is closed, nil if retrievals are not delayed
+	Gate chan struct{}
+	// Blocking is the title of an article whose retrieval never responds until it is cancelled, empty if no article blocks
+	Blocking string
+}
+
+// NewWiki creates a wiki serving the articles described by links
+func NewWiki(links LinkFunc) *Wiki {
+	return &Wiki{
+		links:   links,
+		fetches: make(map[string]int),
+	}
+}
+
+// Fetch retrieves the article of the page URI, it fits wherever the crawling package accepts a page fetcher
+func (wiki *Wiki) Fetch(ctx context.Context, pageURI string) (io.ReadCloser, error) {
+	title := Title(pageURI)
+	if wiki.Gate != nil {
+		<-wiki.Gate
+	}
+	if title == wiki.Blocking {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	wiki.lock.Lock()
+	wiki.fetches[title]++
+	wiki.lock.Unlock()
+
+	links, ok := wiki.links(title)
+	if !ok {
+		return nil, fmt.Errorf("404 Not Found: %s", pageURI)
+	}
+	return ioutil.NopCloser(strings.NewReader(ArticleHTML(links...))), nil
+}
+
+// Fetches returns how often every article was retrieved by its title
+func (wiki *Wiki) Fetches() map[string]int {
+	wiki.lock.Lock()
+	defer wiki.lock.Unlock()
+
+	fetches := make(map[string]int, len(wiki.fetches))
+	for title, count := range wiki.fetches {
+		fetches[title] = count
+	}
+	return fetches
+}
+
+// Pages describes a wiki consisting of the given articles and the titles they link to
+func Pages(pages map[string][]string) LinkFunc {
+	return func(title string) (links []string, ok bool) {
+		links, ok = pages[title]
+		return
+	}
+}
+
+// Chain describes an endless wiki of articles Page_n linking to Page_n+1 only
+func Chain(title string) (links []string, ok bool) {
+	var n int
+	if _, err := fmt.Sscanf(title, "Page_%d", &n); err != nil {
+		return nil, false
+	}
+	return []string{fmt.Sprintf("Page_%d", n+1)}, true
+}
+
+// PageURI returns the URI of the article with the given title
+func PageURI(title string) string {
+	return WikiBase + "/wiki/" + title
+}
+
+// Title returns the title of an article URI
+func Title(pageURI string) string {
+	return strings.TrimPrefix(pageURI, WikiBase+"/wiki/")
+}
+
+// ArticleHTML renders an article linking to the given titles with the content root of Wikipedia,
+// the anchor text of every link is its title
+func ArticleHTML(links ...string) string {
+	builder := strings.Builder{}
+	builder.WriteString(`<html><body><div id="bodyContent"><p>`)
+	for _, link := range links {
+		fmt.Fprintf(&builder, `<a href="/wiki/%s">%s</a> `, link, link)
+	}
+	builder.WriteString(`</p></div></body></html>`)
+	return builder.String()
+}
@@ -0,0 +1,96 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawlingtest
+
+import (
+	"context"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWiki_Fetch(t *testing.T) {
+	tests := []struct {
+		name     string
+		links    LinkFunc
+		title    string
+		wantHTML string
+		wantErr  bool
+	}{
+		{
+			name:     "link the next page of a chain",
+			links:    Chain,
+			title:    "Page_1",
+			wantHTML: ArticleHTML("Page_2"),
+		},
+		{
+			name:    "fail pages missing in a chain",
+			links:   Chain,
+			title:   "Alpha",
+			wantErr: true,
+		},
+		{
+			name:     "link listed pages",
+			links:    Pages(map[string][]string{"Alpha": {"Beta", "Gamma"}}),
+			title:    "Alpha",
+			wantHTML: ArticleHTML("Beta", "Gamma"),
+		},
+		{
+			name:     "link the children and the parent of a tree node",
+			links:    Tree{Branching: 2, Nodes: 7}.Links,
+			title:    Tree{}.Title(1),
+			wantHTML: ArticleHTML(Tree{}.Title(3), Tree{}.Title(4), Tree{}.Title(0)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wiki := NewWiki(tt.links)
+			body, err := wiki.Fetch(context.Background(), PageURI(tt.title))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				html, _ := ioutil.ReadAll(body)
+				if string(html) != tt.wantHTML {
+					t.Errorf("Fetch() = %s, want %s", html, tt.wantHTML)
+				}
+			}
+			if fetches := wiki.Fetches(); !reflect.DeepEqual(fetches, map[string]int{tt.title: 1}) {
+				t.Errorf("Fetches() = %v, want one retrieval of %s", fetches, tt.title)
+			}
+		})
+	}
+}
+
+func TestWiki_Fetch_Blocking(t *testing.T) {
+	wiki := NewWiki(Chain)
+	wiki.Blocking = "Page_0"
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := wiki.Fetch(ctx, PageURI("Page_0")); err != context.DeadlineExceeded {
+		t.Errorf("Fetch() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestTree_Node(t *testing.T) {
+	tree := Tree{Branching: 20, Nodes: 50000}
+	for _, node := range []int{0, 25, 26, 49999} {
+		if got := tree.Node(tree.PageURI(node)); got != node {
+			t.Errorf("Node(PageURI(%d)) = %d", node, got)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"golang.org/x/net/html"
+	"math"
+)
+
+type pageID uint32
+
+const (
+	noParent pageID = math.MaxUint32
+)
+
+func newTitleInterner() *titleInterner {
+	return &titleInterner{ids: make(map[string]pageID)}
+}
+
+// titleInterner maps article titles to compact IDs.
+// Every title is stored exactly once, the traversal itself only passes around pageIDs.
+// As every discovered title gets interned, the interner doubles as set of already visited pages.
+type titleInterner struct {
+	ids    map[string]pageID
+	titles []string
+}
+
+func (interner *titleInterner) Intern(title string) (id pageID, alreadyPresent bool) {
+	if id, alreadyPresent = interner.ids[title]; !alreadyPresent {
+		id = pageID(len(interner.titles))
+		interner.ids[title] = id
+		interner.titles = append(interner.titles, title)
+	}
+	return
+}
+
+func (interner titleInterner) Lookup(title string) (id pageID, ok bool) {
+	id, ok = interner.ids[title]
+	return
+}
+
+func (interner titleInterner) Title(id pageID) string {
+	return interner.titles[id]
+}
+
+func (interner titleInterner) Len() int {
+	return len(interner.titles)
+}
+
+// Truncate forgets all titles with an ID greater or equal to length
+func (interner *titleInterner) Truncate(length int) {
+	if length >= len(interner.titles) {
+		return
+	}
+	for _, title := range interner.titles[length:] {
+		delete(interner.ids, title)
+	}
+	interner.titles = interner.titles[:length]
+}
+
+// parentMap records for every discovered page the page it was discovered on first.
+// As page IDs are dense a plain slice indexed by the child ID is sufficient.
+type parentMap struct {
+	parents []pageID
+}
+
+func (m *parentMap) Set(child, parent pageID) {
+	for int(child) >= len(m.parents) {
+		m.parents = append(m.parents, noParent)
+	}
+	m.parents[child] = parent
+}
+
+func (m parentMap) Parent(child pageID) (parent pageID, ok bool) {
+	if int(child) >= len(m.parents) {
+		return noParent, false
+	}
+	parent = m.parents[child]
+	ok = parent != noParent
+	return
+}
+
+// Truncate forgets the parents of all pages with an ID greater or equal to length
+func (m *parentMap) Truncate(length int) {
+	if length < len(m.parents) {
+		m.parents = m.parents[:length]
+	}
+}
+
+// PathTo walks up the predecessors of the given page and returns the path from the root to the page
+func (m parentMap) PathTo(id pageID) (path []pageID) {
+	for current, ok := id, true; ok; current, ok = m.Parent(current) {
+		path = append(path, current)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return
+}
+
+type tokenStack struct {
+	tokens []html.Token
+}
+
+func (stack *tokenStack) Push(token html.Token) {
+	stack.tokens = append(stack.tokens, token)
+}
+
+func (stack tokenStack) Peek() (token html.Token, ok bool) {
+	if ok = len(stack.tokens) > 0; !ok {
+		return
+	}
+
+	n := len(stack.tokens) - 1
+	token = stack.tokens[n]
+	return
+}
+
+func (stack *tokenStack) Pop() (token html.Token, ok bool) {
+	if ok = len(stack.tokens) > 0; !ok {
+		return
+	}
+
+	n := len(stack.tokens) - 1
+	token = stack.tokens[n]
+	stack.tokens = stack.tokens[:n]
+	return
+}
+
+func (stack tokenStack) Empty() bool {
+	return len(stack.tokens) == 0
+}
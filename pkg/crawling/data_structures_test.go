@@ -20,104 +20,182 @@ import (
 	"testing"
 )
 
-func Test_stringSet_Add(t *testing.T) {
+func Test_titleInterner_Intern(t *testing.T) {
 	type fields struct {
-		items map[string]bool
+		titles []string
 	}
 	type args struct {
-		value string
+		title string
 	}
 	tests := []struct {
 		name               string
 		fields             fields
 		args               args
+		wantID             pageID
 		wantAlreadyPresent bool
-		resultingSetSize   int
+		resultingSize      int
 	}{
 		{
-			name: "Test add into empty set",
+			name:               "Test intern into empty interner",
+			fields:             fields{},
+			args:               args{title: "Times_New_Roman"},
+			wantID:             0,
+			wantAlreadyPresent: false,
+			resultingSize:      1,
+		},
+		{
+			name: "Test intern new title",
 			fields: fields{
-				items: make(map[string]bool),
-			},
-			args: args{
-				value: "hello, world",
+				titles: []string{"Times_New_Roman"},
 			},
+			args:               args{title: "The_Times"},
+			wantID:             1,
 			wantAlreadyPresent: false,
-			resultingSetSize:   1,
+			resultingSize:      2,
 		},
 		{
-			name: "Test add already existing string",
+			name: "Test intern already existing title",
 			fields: fields{
-				items: map[string]bool{
-					"hello, world": true,
-				},
-			},
-			args: args{
-				value: "hello, world",
+				titles: []string{"Times_New_Roman", "The_Times"},
 			},
+			args:               args{title: "Times_New_Roman"},
+			wantID:             0,
 			wantAlreadyPresent: true,
-			resultingSetSize:   1,
+			resultingSize:      2,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			set := &stringSet{
-				items: tt.fields.items,
+			interner := newTitleInterner()
+			for _, title := range tt.fields.titles {
+				interner.Intern(title)
 			}
-			if gotAlreadyPresent := set.Add(tt.args.value); gotAlreadyPresent != tt.wantAlreadyPresent {
-				t.Errorf("Add() = %v, want %v", gotAlreadyPresent, tt.wantAlreadyPresent)
+			gotID, gotAlreadyPresent := interner.Intern(tt.args.title)
+			if gotID != tt.wantID {
+				t.Errorf("Intern() gotID = %v, want %v", gotID, tt.wantID)
 			}
-
-			if tt.resultingSetSize != len(set.items) {
-				t.Errorf("Expected resulting set to have size %d but got %d", tt.resultingSetSize, len(set.items))
+			if gotAlreadyPresent != tt.wantAlreadyPresent {
+				t.Errorf("Intern() gotAlreadyPresent = %v, want %v", gotAlreadyPresent, tt.wantAlreadyPresent)
+			}
+			if interner.Len() != tt.resultingSize {
+				t.Errorf("Expected resulting interner to have size %d but got %d", tt.resultingSize, interner.Len())
+			}
+			if gotTitle := interner.Title(gotID); gotTitle != tt.args.title {
+				t.Errorf("Title() = %v, want %v", gotTitle, tt.args.title)
 			}
 		})
 	}
 }
 
-func Test_stringSet_Contains(t *testing.T) {
+func Test_titleInterner_Lookup(t *testing.T) {
 	type fields struct {
-		items map[string]bool
+		titles []string
 	}
 	type args struct {
-		value string
+		title string
 	}
 	tests := []struct {
-		name          string
-		fields        fields
-		args          args
-		wantContained bool
+		name   string
+		fields fields
+		args   args
+		wantID pageID
+		wantOk bool
 	}{
 		{
-			name: "check for value in empty set",
+			name:   "lookup title in empty interner",
+			fields: fields{},
+			args:   args{title: "Times_New_Roman"},
+			wantOk: false,
+		},
+		{
+			name: "lookup actually interned title",
 			fields: fields{
-				items: make(map[string]bool),
-			},
-			args: args{
-				value: "hello, world",
+				titles: []string{"Times_New_Roman", "The_Times"},
 			},
-			wantContained: false,
+			args:   args{title: "The_Times"},
+			wantID: 1,
+			wantOk: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interner := newTitleInterner()
+			for _, title := range tt.fields.titles {
+				interner.Intern(title)
+			}
+			gotID, gotOk := interner.Lookup(tt.args.title)
+			if gotOk != tt.wantOk {
+				t.Errorf("Lookup() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+			if gotOk && gotID != tt.wantID {
+				t.Errorf("Lookup() gotID = %v, want %v", gotID, tt.wantID)
+			}
+		})
+	}
+}
+
+func Test_titleInterner_Truncate(t *testing.T) {
+	interner := newTitleInterner()
+	for _, title := range []string{"Times_New_Roman", "The_Times", "Serif"} {
+		interner.Intern(title)
+	}
+
+	interner.Truncate(1)
+
+	if interner.Len() != 1 {
+		t.Errorf("Expected interner to have size 1 after truncate but got %d", interner.Len())
+	}
+
+	if _, ok := interner.Lookup("The_Times"); ok {
+		t.Error("Expected truncated title to be forgotten")
+	}
+
+	if id, alreadyPresent := interner.Intern("Serif"); alreadyPresent || id != 1 {
+		t.Errorf("Intern() after truncate = %d, %v, want 1, false", id, alreadyPresent)
+	}
+}
+
+func Test_parentMap_PathTo(t *testing.T) {
+	type fields struct {
+		edges [][2]pageID
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		target   pageID
+		wantPath []pageID
+	}{
+		{
+			name:     "path to root",
+			fields:   fields{},
+			target:   0,
+			wantPath: []pageID{0},
 		},
 		{
-			name: "check for actually contained value",
+			name: "path through multiple levels",
 			fields: fields{
-				items: map[string]bool{
-					"hello, world": true,
-				},
+				edges: [][2]pageID{{1, 0}, {2, 0}, {3, 1}, {4, 3}},
 			},
-			args: args{
-				value: "hello, world",
+			target:   4,
+			wantPath: []pageID{0, 1, 3, 4},
+		},
+		{
+			name: "path to sibling branch",
+			fields: fields{
+				edges: [][2]pageID{{1, 0}, {2, 0}, {3, 1}, {4, 2}},
 			},
-			wantContained: true,
+			target:   4,
+			wantPath: []pageID{0, 2, 4},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			set := &stringSet{
-				items: tt.fields.items,
+			m := parentMap{}
+			for _, edge := range tt.fields.edges {
+				m.Set(edge[0], edge[1])
 			}
-			if gotContained := set.Contains(tt.args.value); gotContained != tt.wantContained {
-				t.Errorf("Contains() = %v, want %v", gotContained, tt.wantContained)
+			if gotPath := m.PathTo(tt.target); !reflect.DeepEqual(gotPath, tt.wantPath) {
+				t.Errorf("PathTo() = %v, want %v", gotPath, tt.wantPath)
 			}
 		})
 	}
@@ -0,0 +1,44 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package crawling searches the shortest chain of links leading from one wiki article to another.
+//
+// A WikiCrawler runs a breadth first search over the links of the article pages of a MediaWiki site
+// e.g. Wikipedia, pages are retrieved lazily while the search proceeds:
+//
+//	crawler, err := crawling.NewWikiCrawler(
+//		"https://en.wikipedia.org/wiki/Times_New_Roman",
+//		"https://en.wikipedia.org/wiki/Helvetica",
+//		crawling.WithMaxHops(6),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	result, err := crawler.SearchShortestPath(ctx)
+//	if errors.Is(err, crawling.ErrMaxHopsReached) {
+//		// no path with at most 6 links exists
+//	}
+//	for _, page := range result.Path() {
+//		fmt.Println(page.Title, page.URI)
+//	}
+//
+// Titles and pages of other wikis are resolved to PageReferences by ResolvePages with the built-in or custom SiteProfiles,
+// NewWikiCrawlerForPages creates a crawler for them.
+// The crawler is configured by Options e.g. to share fetched pages between crawlers via a PageCache,
+// to follow interlanguage links or to observe the search.
+// A crawler runs a single query, an Engine creates crawlers for many queries sharing the links of all processed pages.
+//
+// Failures are reported as sentinel errors like ErrInvalidArticleURL or as FetchError and ParseError,
+// use errors.Is and errors.As to inspect them.
+package crawling
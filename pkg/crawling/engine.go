@@ -0,0 +1,47 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+// Engine runs any number of searches, also concurrently, sharing the links of all pages processed by any of them.
+// Searches of pages in the same region of a wiki profit the most as they process mostly the same pages.
+type Engine struct {
+	links   *LinkCache
+	options []Option
+}
+
+// NewEngine creates an engine keeping about linkCacheBytes of links in memory,
+// the options apply to every search of the engine e.g. to retrieve pages via a PageCache
+func NewEngine(linkCacheBytes uint64, options ...Option) *Engine {
+	return &Engine{
+		links:   NewLinkCache(linkCacheBytes),
+		options: options,
+	}
+}
+
+// NewSearch creates a crawler searching the shortest path from start to target via the link cache of the engine.
+// The options of the search are applied after the options of the engine.
+// Every crawler is meant for a single query, create a new one for every search.
+func (engine *Engine) NewSearch(start, target PageReference, options ...Option) *WikiCrawler {
+	searchOptions := make([]Option, 0, len(engine.options)+len(options)+1)
+	searchOptions = append(searchOptions, engine.options...)
+	searchOptions = append(searchOptions, options...)
+	searchOptions = append(searchOptions, WithLinkCache(engine.links))
+	return NewWikiCrawlerForPages(start, target, searchOptions...)
+}
+
+// LinkCache returns the cache shared by all searches of the engine e.g. to expose its statistics
+func (engine *Engine) LinkCache() *LinkCache {
+	return engine.links
+}
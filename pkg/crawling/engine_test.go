@@ -0,0 +1,198 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"context"
+	"fmt"
+	"github.com/baez90/shortest-path/pkg/crawling/crawlingtest"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withFetcher lets the crawler retrieve pages via fetch
+func withFetcher(fetch pageFetcher) Option {
+	return func(crawler *WikiCrawler) {
+		crawler.fetchPage = fetch
+	}
+}
+
+func TestEngine_NewSearch(t *testing.T) {
+	graph := crawlingtest.Tree{Branching: 3, Nodes: 121}
+	tests := []struct {
+		name           string
+		linkCacheBytes uint64
+		targets        []int
+		wantMaxFetches int
+	}{
+		{
+			name:           "retrieve every page once",
+			linkCacheBytes: 1 << 20,
+			targets:        []int{40, 41, 13, 120},
+			wantMaxFetches: 1,
+		},
+		{
+			name:           "retrieve pages again without link cache",
+			linkCacheBytes: 0,
+			targets:        []int{40, 41},
+			wantMaxFetches: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wiki := crawlingtest.NewWiki(graph.Links)
+			engine := NewEngine(tt.linkCacheBytes, WithMaxHops(10), withFetcher(wiki.Fetch))
+
+			start, _ := parseArticleURL(graph.PageURI(0), BuiltinSiteProfiles())
+			for _, target := range tt.targets {
+				targetRef, _ := parseArticleURL(graph.PageURI(target), BuiltinSiteProfiles())
+				got, err := engine.NewSearch(start, targetRef).SearchShortestPath(context.Background())
+				if err != nil {
+					t.Fatalf("SearchShortestPath() to %d error = %v", target, err)
+				}
+				want, _ := treeCrawler(graph, target, 10).SearchShortestPath(context.Background())
+				if got.Hops() != want.Hops() {
+					t.Errorf("SearchShortestPath() to %d hops = %d, want %d", target, got.Hops(), want.Hops())
+				}
+			}
+
+			maxFetches := 0
+			for _, count := range wiki.Fetches() {
+				if count > maxFetches {
+					maxFetches = count
+				}
+			}
+			if maxFetches != tt.wantMaxFetches {
+				t.Errorf("retrieved pages up to %d times, want %d", maxFetches, tt.wantMaxFetches)
+			}
+		})
+	}
+}
+
+func TestEngine_NewSearch_Settings(t *testing.T) {
+	pages := map[string]string{
+		"https://de.wikipedia.org/wiki/Schriftart": `<div id="bodyContent"><p>Eine <a href="/wiki/Serife">Serife</a> ist ein Strich.</p></div>
+<div id="p-lang"><ul><li class="interlanguage-link interwiki-en"><a href="https://en.wikipedia.org/wiki/Typeface">English</a></li></ul></div>`,
+		"https://de.wikipedia.org/wiki/Serife":   `<div id="bodyContent"><p>Serifen</p></div>`,
+		"https://en.wikipedia.org/wiki/Typeface": `<div id="bodyContent"><p><a href="/wiki/Times_New_Roman">Times New Roman</a></p></div>`,
+	}
+	engine := NewEngine(1<<20, WithMaxHops(3), withFetcher(func(ctx context.Context, pageURI string) (io.ReadCloser, error) {
+		page, ok := pages[pageURI]
+		if !ok {
+			return nil, fmt.Errorf("no page %s", pageURI)
+		}
+		return ioutil.NopCloser(strings.NewReader(page)), nil
+	}))
+
+	resolve := func(uri string) PageReference {
+		ref, _ := parseArticleURL(uri, BuiltinSiteProfiles())
+		return ref
+	}
+	schriftart := resolve("https://de.wikipedia.org/wiki/Schriftart")
+
+	// searches run one after another, every search has to work with the links cached by its predecessors
+	tests := []struct {
+		name        string
+		target      string
+		options     []Option
+		wantHops    int
+		wantContext bool
+		wantErr     bool
+	}{
+		{
+			name:     "search within one language edition",
+			target:   "https://de.wikipedia.org/wiki/Serife",
+			wantHops: 1,
+		},
+		{
+			name:     "follow interlanguage links not collected by the previous search",
+			target:   "https://en.wikipedia.org/wiki/Times_New_Roman",
+			options:  []Option{WithInterlanguageLinks()},
+			wantHops: 2,
+		},
+		{
+			name:    "ignore cached interlanguage links without interlanguage links",
+			target:  "https://en.wikipedia.org/wiki/Times_New_Roman",
+			wantErr: true,
+		},
+		{
+			name:        "capture link context not captured by the previous searches",
+			target:      "https://de.wikipedia.org/wiki/Serife",
+			options:     []Option{WithLinkContext()},
+			wantHops:    1,
+			wantContext: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.NewSearch(schriftart, resolve(tt.target), tt.options...).SearchShortestPath(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SearchShortestPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result.Hops() != tt.wantHops {
+				t.Errorf("SearchShortestPath() hops = %d, want %d", result.Hops(), tt.wantHops)
+			}
+			if path := result.Path(); tt.wantContext && (path[len(path)-1].Link == nil || path[len(path)-1].Link.AnchorText != "Serife") {
+				t.Errorf("SearchShortestPath() link = %+v, want anchor text Serife", path[len(path)-1].Link)
+			}
+		})
+	}
+}
+
+// fetchCountingObserver counts the pages retrieved and the pages answered by the link cache
+type fetchCountingObserver struct {
+	NopObserver
+	fetched int
+	cached  int
+}
+
+func (observer *fetchCountingObserver) OnPageFetched(string, time.Duration) { observer.fetched++ }
+func (observer *fetchCountingObserver) OnLinksCached(string)                { observer.cached++ }
+
+func TestEngine_NewSearch_CachedPages(t *testing.T) {
+	graph := crawlingtest.Tree{Branching: 3, Nodes: 40}
+	engine := NewEngine(1<<20, WithMaxHops(10), withFetcher(graph.Fetch))
+	start, _ := parseArticleURL(graph.PageURI(0), BuiltinSiteProfiles())
+	target, _ := parseArticleURL(graph.PageURI(14), BuiltinSiteProfiles())
+
+	// levels 0 and 1 are processed completely, on level 2 the target is discovered on the first page
+	const processedPages = 5
+	tests := []struct {
+		name        string
+		wantFetched int
+		wantCached  int
+	}{
+		{name: "fetch every page of the first search", wantFetched: processedPages},
+		{name: "take every page of the repeated search from the link cache", wantCached: processedPages},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer := &fetchCountingObserver{}
+			crawler := engine.NewSearch(start, target, WithObserver(observer))
+			if _, err := crawler.SearchShortestPath(context.Background()); err != nil {
+				t.Fatalf("SearchShortestPath() error = %v", err)
+			}
+			if crawler.FetchedPages() != uint(tt.wantFetched) || crawler.CachedPages() != uint(tt.wantCached) {
+				t.Errorf("fetched %d and cached %d pages, want %d and %d", crawler.FetchedPages(), crawler.CachedPages(), tt.wantFetched, tt.wantCached)
+			}
+			if observer.fetched != tt.wantFetched || observer.cached != tt.wantCached {
+				t.Errorf("observed %d fetched and %d cached pages, want %d and %d", observer.fetched, observer.cached, tt.wantFetched, tt.wantCached)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrMaxHopsReached is returned if the target was not reached within the maximum number of hops
+	ErrMaxHopsReached = errors.New("reached max hops")
+	// ErrInvalidArticleURL is returned for page URLs which are no article URLs of a wiki
+	ErrInvalidArticleURL = errors.New("invalid article URL")
+	// ErrInvalidWikiURL is returned for base URLs of wikis which are no http(s) URLs
+	ErrInvalidWikiURL = errors.New("invalid wiki URL")
+	// ErrInvalidLanguage is returned for malformed language codes of Wikipedia editions
+	ErrInvalidLanguage = errors.New("invalid language code")
+	// ErrEmptyTitle is returned for page titles consisting of white space only
+	ErrEmptyTitle = errors.New("empty page title")
+	// ErrNoWiki is returned if a title has to be resolved but no wiki was given
+	ErrNoWiki = errors.New("no wiki to resolve the title in")
+	// ErrDifferentWikis is returned if start and target page have to but do not belong to the same wiki
+	ErrDifferentWikis = errors.New("pages belong to different wikis")
+	// ErrNoSiteProfile is returned for URLs of wikis which are not covered by any site profile
+	ErrNoSiteProfile = errors.New("no site profile")
+	// ErrInvalidCheckpoint is returned if a checkpoint cannot be decoded or was written by an incompatible version
+	ErrInvalidCheckpoint = errors.New("invalid checkpoint")
+	// ErrPageNotFound matches a FetchError of a page which does not exist
+	ErrPageNotFound = errors.New("page not found")
+
+	errPathFound = errors.New("path found")
+)
+
+// FetchError is returned if a page could not be retrieved
+type FetchError struct {
+	PageURI string
+	// StatusCode is the status of the response if the wiki answered with a status other than 2xx, 0 otherwise
+	StatusCode int
+	// Err is the cause if no response was received, nil otherwise
+	Err error
+}
+
+func (e *FetchError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("failed to fetch %s: %v", e.PageURI, e.Err)
+	}
+	return fmt.Sprintf("failed to fetch %s: %d %s", e.PageURI, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports ErrPageNotFound for responses with status 404
+func (e *FetchError) Is(target error) bool {
+	return target == ErrPageNotFound && e.StatusCode == http.StatusNotFound
+}
+
+// ParseError is returned if a page was retrieved but could not be parsed
+type ParseError struct {
+	PageURI string
+	Err     error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("failed to parse %s: %v", e.PageURI, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
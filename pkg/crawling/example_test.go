@@ -0,0 +1,60 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/baez90/shortest-path/pkg/crawling"
+)
+
+func ExampleNewWikiCrawler() {
+	crawler, err := crawling.NewWikiCrawler(
+		"https://en.wikipedia.org/wiki/Times_New_Roman",
+		"https://en.wikipedia.org/wiki/Helvetica",
+		crawling.WithMaxHops(6),
+		crawling.WithLinkContext(),
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	result, err := crawler.SearchShortestPath(context.Background())
+	switch {
+	case errors.Is(err, crawling.ErrMaxHopsReached):
+		fmt.Println("no path with at most 6 links")
+	case err != nil:
+		fmt.Println(err)
+	default:
+		for _, page := range result.Path() {
+			fmt.Println(page.Title)
+		}
+	}
+}
+
+func ExampleResolvePages() {
+	start, target, err := crawling.ResolvePages("Times New Roman", "Helvetica", "https://en.wikipedia.org", crawling.BuiltinSiteProfiles())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(start.URI())
+	fmt.Println(target.URI())
+	// Output:
+	// https://en.wikipedia.org/wiki/Times_New_Roman
+	// https://en.wikipedia.org/wiki/Helvetica
+}
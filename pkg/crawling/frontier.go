@@ -0,0 +1,177 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	pageIDSize = 4
+)
+
+// frontier is the FIFO queue of all pages of a single BFS level
+type frontier interface {
+	Push(id pageID) error
+	Len() uint64
+	// Iterate calls fn for every queued page in insertion order until fn returns an error.
+	// Iterating does not consume the queued pages.
+	Iterate(fn func(id pageID) error) error
+	// Close releases all resources held by the frontier
+	Close() error
+}
+
+// newSpillingFrontier creates a frontier which keeps up to memoryLimit bytes of page IDs in memory.
+// As soon as the limit is exceeded the buffered IDs are written to a new segment file in spillDirectory.
+// A memoryLimit of 0 disables spilling, limits which are no multiple of the size of a page ID are rounded up.
+func newSpillingFrontier(spillDirectory string, memoryLimit uint64) *spillingFrontier {
+	bufferLimit := memoryLimit / pageIDSize
+	if memoryLimit%pageIDSize != 0 {
+		bufferLimit++
+	}
+	return &spillingFrontier{
+		spillDirectory: spillDirectory,
+		bufferLimit:    int(bufferLimit),
+	}
+}
+
+type spillingFrontier struct {
+	spillDirectory string
+	segmentDir     string
+	segments       []string
+	bufferLimit    int
+	buffer         []pageID
+	length         uint64
+}
+
+func (f *spillingFrontier) Push(id pageID) (err error) {
+	f.buffer = append(f.buffer, id)
+	f.length++
+
+	if f.bufferLimit > 0 && len(f.buffer) >= f.bufferLimit {
+		err = f.spill()
+	}
+	return
+}
+
+func (f spillingFrontier) Len() uint64 {
+	return f.length
+}
+
+func (f spillingFrontier) Iterate(fn func(id pageID) error) (err error) {
+	for _, segment := range f.segments {
+		if err = iterateSegment(segment, fn); err != nil {
+			return
+		}
+	}
+
+	return f.iterateBuffer(fn)
+}
+
+func (f spillingFrontier) iterateBuffer(fn func(id pageID) error) (err error) {
+	for _, id := range f.buffer {
+		if err = fn(id); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (f *spillingFrontier) Close() (err error) {
+	f.buffer = nil
+	f.segments = nil
+	f.length = 0
+	if f.segmentDir != "" {
+		err = os.RemoveAll(f.segmentDir)
+		f.segmentDir = ""
+	}
+	return
+}
+
+// spill writes the in-memory buffer to a new segment file and resets the buffer
+func (f *spillingFrontier) spill() (err error) {
+	if f.segmentDir == "" {
+		if f.segmentDir, err = ioutil.TempDir(f.spillDirectory, "shortest-path-frontier"); err != nil {
+			return
+		}
+	}
+
+	segment := filepath.Join(f.segmentDir, fmt.Sprintf("segment-%06d.bin", len(f.segments)))
+	var file *os.File
+	if file, err = os.Create(segment); err != nil {
+		return
+	}
+
+	writer := bufio.NewWriter(file)
+	if err = writePageIDs(writer, f.iterateBuffer); err != nil {
+		_ = file.Close()
+		return
+	}
+
+	if err = writer.Flush(); err != nil {
+		_ = file.Close()
+		return
+	}
+
+	if err = file.Close(); err != nil {
+		return
+	}
+
+	f.segments = append(f.segments, segment)
+	f.buffer = f.buffer[:0]
+	return
+}
+
+func iterateSegment(segment string, fn func(id pageID) error) (err error) {
+	var file *os.File
+	if file, err = os.Open(segment); err != nil {
+		return
+	}
+	defer file.Close()
+
+	return readPageIDs(bufio.NewReader(file), fn)
+}
+
+// writePageIDs encodes every page ID passed by iterate in the binary format of segments
+func writePageIDs(writer io.Writer, iterate func(fn func(id pageID) error) error) error {
+	encoded := make([]byte, pageIDSize)
+	return iterate(func(id pageID) (err error) {
+		binary.LittleEndian.PutUint32(encoded, uint32(id))
+		_, err = writer.Write(encoded)
+		return
+	})
+}
+
+// readPageIDs decodes page IDs written by writePageIDs and calls fn for every ID until the reader is exhausted
+func readPageIDs(reader io.Reader, fn func(id pageID) error) (err error) {
+	encoded := make([]byte, pageIDSize)
+	for {
+		if _, err = io.ReadFull(reader, encoded); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return
+		}
+
+		if err = fn(pageID(binary.LittleEndian.Uint32(encoded))); err != nil {
+			return
+		}
+	}
+}
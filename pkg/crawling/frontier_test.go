@@ -0,0 +1,145 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func Test_spillingFrontier(t *testing.T) {
+	type args struct {
+		memoryLimit uint64
+		pushed      int
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantSegments int
+	}{
+		{
+			name: "keep everything in memory without limit",
+			args: args{
+				memoryLimit: 0,
+				pushed:      100,
+			},
+			wantSegments: 0,
+		},
+		{
+			name: "keep everything in memory below limit",
+			args: args{
+				memoryLimit: 1024,
+				pushed:      100,
+			},
+			wantSegments: 0,
+		},
+		{
+			name: "spill multiple segments",
+			args: args{
+				memoryLimit: 10 * pageIDSize,
+				pushed:      105,
+			},
+			wantSegments: 10,
+		},
+		{
+			name: "spill every page below the size of a page ID",
+			args: args{
+				memoryLimit: 1,
+				pushed:      3,
+			},
+			wantSegments: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spillDirectory, err := ioutil.TempDir("", "frontier-test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(spillDirectory)
+
+			f := newSpillingFrontier(spillDirectory, tt.args.memoryLimit)
+			var wantIDs []pageID
+			for i := 0; i < tt.args.pushed; i++ {
+				wantIDs = append(wantIDs, pageID(i))
+				if err := f.Push(pageID(i)); err != nil {
+					t.Fatalf("Push() error = %v", err)
+				}
+			}
+
+			if f.Len() != uint64(tt.args.pushed) {
+				t.Errorf("Len() = %d, want %d", f.Len(), tt.args.pushed)
+			}
+
+			if len(f.segments) != tt.wantSegments {
+				t.Errorf("Expected %d segments but got %d", tt.wantSegments, len(f.segments))
+			}
+
+			for pass := 0; pass < 2; pass++ {
+				var gotIDs []pageID
+				if err := f.Iterate(func(id pageID) error {
+					gotIDs = append(gotIDs, id)
+					return nil
+				}); err != nil {
+					t.Fatalf("Iterate() error = %v", err)
+				}
+				if !reflect.DeepEqual(gotIDs, wantIDs) {
+					t.Errorf("Iterate() pass %d = %v, want %v", pass, gotIDs, wantIDs)
+				}
+			}
+
+			if err := f.Close(); err != nil {
+				t.Errorf("Close() error = %v", err)
+			}
+
+			if entries, _ := ioutil.ReadDir(spillDirectory); len(entries) != 0 {
+				t.Errorf("Expected spill directory to be empty after close but found %d entries", len(entries))
+			}
+		})
+	}
+}
+
+func Test_spillingFrontier_IterateStopsOnError(t *testing.T) {
+	spillDirectory, err := ioutil.TempDir("", "frontier-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(spillDirectory)
+
+	f := newSpillingFrontier(spillDirectory, 2*pageIDSize)
+	defer f.Close()
+	for i := 0; i < 10; i++ {
+		_ = f.Push(pageID(i))
+	}
+
+	stopErr := errors.New("stop")
+	visited := 0
+	if err := f.Iterate(func(id pageID) error {
+		visited++
+		if id == 4 {
+			return stopErr
+		}
+		return nil
+	}); err != stopErr {
+		t.Errorf("Iterate() error = %v, want %v", err, stopErr)
+	}
+
+	if visited != 5 {
+		t.Errorf("Expected iteration to stop after 5 pages but visited %d", visited)
+	}
+}
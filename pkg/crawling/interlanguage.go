@@ -0,0 +1,137 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/html"
+	"net/url"
+	"strings"
+)
+
+const (
+	// qualifiedTitleSeparator separates the wiki from the title in interned titles of other wikis,
+	// escaped titles never contain spaces
+	qualifiedTitleSeparator = " "
+)
+
+// LinkKind is the type of the edge between two pages
+type LinkKind uint8
+
+const (
+	// ArticleLink is a link between two articles of the same wiki
+	ArticleLink LinkKind = iota
+	// InterlanguageLink is a link to the same topic in another language edition
+	InterlanguageLink
+)
+
+func (kind LinkKind) String() string {
+	if kind == InterlanguageLink {
+		return "interlanguage"
+	}
+	return "article"
+}
+
+// pageLink is a newly discovered page and the kind of link it was discovered by
+type pageLink struct {
+	id   pageID
+	kind LinkKind
+}
+
+// linkScope tells the parser which wiki the parsed page belongs to and which links to collect
+type linkScope struct {
+	site SiteProfile
+	// wiki is the base URL of the parsed page, empty for pages of the start wiki
+	wiki string
+	// startWiki is the base URL of the start page, titles of this wiki are interned without qualification
+	startWiki string
+	// interlanguage enables collecting links to other language editions
+	interlanguage bool
+}
+
+func (scope linkScope) key(wiki, title string) string {
+	return qualifiedTitle(scope.startWiki, wiki, title)
+}
+
+// qualifiedTitle returns the key to intern the title of wiki with.
+// Titles of the start wiki are kept as they are to not spend any memory on the qualification in single wiki searches,
+// titles of other wikis are qualified with the canonical base URL of their wiki to match regardless of scheme and mobile site.
+func qualifiedTitle(startWiki, wiki, title string) string {
+	if wiki == "" || SameWiki(startWiki, wiki) {
+		return title
+	}
+	return canonicalWikiBase(wiki) + qualifiedTitleSeparator + title
+}
+
+// splitQualifiedTitle returns the wiki and the title of an interned title, the wiki is empty for the start wiki
+func splitQualifiedTitle(key string) (wiki, title string) {
+	if idx := strings.Index(key, qualifiedTitleSeparator); idx >= 0 {
+		return key[:idx], key[idx+len(qualifiedTitleSeparator):]
+	}
+	return "", key
+}
+
+// extractInterlanguageLinks collects the links of the language list following the content root,
+// the tokenizer has to be positioned after the content root
+func extractInterlanguageLinks(tokenizer *html.Tokenizer, scope linkScope, titles *titleInterner) (links []pageLink) {
+	inLanguageList := false
+	for tokenizer.Next() != html.ErrorToken {
+		token := tokenizer.Token()
+		if token.Type != html.StartTagToken && token.Type != html.SelfClosingTagToken {
+			continue
+		}
+
+		switch {
+		case token.Data == "li":
+			inLanguageList = hasClass(token, "interlanguage-link")
+		case token.Data == "a" && inLanguageList:
+			inLanguageList = false
+			for _, attr := range token.Attr {
+				if attr.Key != "href" {
+					continue
+				}
+				key, ok := scope.interlanguageKey(attr.Val)
+				if !ok {
+					continue
+				}
+				if id, alreadyPresent := titles.Intern(key); !alreadyPresent {
+					log.Debugf("Enqueuing discovered interlanguage link %s", attr.Val)
+					links = append(links, pageLink{id: id, kind: InterlanguageLink})
+				}
+			}
+		}
+	}
+	return
+}
+
+// interlanguageKey resolves the absolute URL of an interlanguage link to an interned title,
+// the other language editions are expected to share the site profile of the parsed page
+func (scope linkScope) interlanguageKey(href string) (key string, ok bool) {
+	parsed, err := url.Parse(href)
+	if err != nil || parsed.Host == "" {
+		return
+	}
+
+	// links to other wikis are usually protocol relative
+	if parsed.Scheme == "" {
+		parsed.Scheme = "https"
+	}
+
+	title, isArticle := scope.site.articleTitle(parsed.RequestURI())
+	if !isArticle {
+		return
+	}
+	return scope.key(wikiBase(parsed), title), true
+}
@@ -0,0 +1,168 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"container/list"
+	"io"
+	"sync"
+)
+
+const (
+	// cachedLinkOverhead approximates the memory of a cached link besides its title
+	cachedLinkOverhead = 48
+	// cachedContextOverhead approximates the memory of a cached link context besides its texts
+	cachedContextOverhead = 48
+)
+
+// cachedLink is a link of a page independent of any search, its key is always qualified with the wiki of the title
+type cachedLink struct {
+	key     string
+	kind    LinkKind
+	context *LinkContext
+}
+
+// pageLinks are the links of a page in the order of their first occurrence, every linked page is contained once
+type pageLinks struct {
+	uri   string
+	links []cachedLink
+	// interlanguage is set if the interlanguage links of the page were collected as well
+	interlanguage bool
+	// contexts is set if the context of every article link was captured
+	contexts bool
+	size     uint64
+}
+
+// covers reports whether the links contain everything a search with the given settings requires
+func (links pageLinks) covers(interlanguage, contexts bool) bool {
+	return (links.interlanguage || !interlanguage) && (links.contexts || !contexts)
+}
+
+// parsePageLinks collects the links of the page of the given wiki like extractLinksFromContent
+// but without skipping pages already discovered by a search
+func parsePageLinks(body io.Reader, site SiteProfile, wiki string, interlanguage, contexts bool) (links *pageLinks, err error) {
+	titles := newTitleInterner()
+	var linkContexts map[pageID]LinkContext
+	if contexts {
+		linkContexts = make(map[pageID]LinkContext)
+	}
+
+	// without a start wiki every title is qualified with its wiki
+	scope := linkScope{site: site, wiki: wiki, interlanguage: interlanguage}
+	var found []pageLink
+	if found, err = extractLinksFromContent(body, scope, titles, linkContexts); err != nil {
+		return
+	}
+
+	links = &pageLinks{
+		links:         make([]cachedLink, 0, len(found)),
+		interlanguage: interlanguage,
+		contexts:      contexts,
+	}
+	for _, link := range found {
+		cached := cachedLink{key: titles.Title(link.id), kind: link.kind}
+		links.size += uint64(len(cached.key)) + cachedLinkOverhead
+		if linkContext, ok := linkContexts[link.id]; ok {
+			cached.context = &linkContext
+			links.size += uint64(len(linkContext.AnchorText)+len(linkContext.Section)+len(linkContext.Snippet)) + cachedContextOverhead
+		}
+		links.links = append(links.links, cached)
+	}
+	return
+}
+
+// LinkCache keeps the links of recently processed pages in memory to share them between searches.
+// Compared to a PageCache the links of a page take a fraction of the memory of its HTML and need not be parsed again.
+// If the cached links exceed the memory limit the links of the least recently used pages are evicted.
+// It is safe for concurrent use by multiple crawlers.
+type LinkCache struct {
+	maxBytes uint64
+	lock     sync.Mutex
+	size     uint64
+	entries  map[string]*list.Element
+	recency  *list.List
+	hits     uint64
+	misses   uint64
+}
+
+// NewLinkCache creates a cache keeping about maxBytes of links in memory, 0 disables caching
+func NewLinkCache(maxBytes uint64) *LinkCache {
+	return &LinkCache{
+		maxBytes: maxBytes,
+		entries:  make(map[string]*list.Element),
+		recency:  list.New(),
+	}
+}
+
+// Len returns the number of pages whose links are cached
+func (cache *LinkCache) Len() int {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.recency.Len()
+}
+
+// Stats returns the number of processed pages whose links were cached and the number of pages which had to be retrieved
+func (cache *LinkCache) Stats() (hits, misses uint64) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.hits, cache.misses
+}
+
+// get returns the cached links of the page if they cover the settings of the search
+func (cache *LinkCache) get(pageURI string, interlanguage, contexts bool) (*pageLinks, bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	element, ok := cache.entries[pageURI]
+	if !ok || !element.Value.(*pageLinks).covers(interlanguage, contexts) {
+		cache.misses++
+		return nil, false
+	}
+	cache.hits++
+	cache.recency.MoveToFront(element)
+	return element.Value.(*pageLinks), true
+}
+
+// put caches the links of the page, links collected with fewer settings are replaced
+func (cache *LinkCache) put(pageURI string, links *pageLinks) {
+	links.uri = pageURI
+	if links.size > cache.maxBytes {
+		return
+	}
+
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	if element, ok := cache.entries[pageURI]; ok {
+		cached := element.Value.(*pageLinks)
+		// another crawler might have processed the same page meanwhile
+		if cached.covers(links.interlanguage, links.contexts) {
+			cache.recency.MoveToFront(element)
+			return
+		}
+		cache.recency.Remove(element)
+		cache.size -= cached.size
+	}
+
+	cache.entries[pageURI] = cache.recency.PushFront(links)
+	cache.size += links.size
+
+	for cache.size > cache.maxBytes {
+		oldest := cache.recency.Back()
+		evicted := cache.recency.Remove(oldest).(*pageLinks)
+		delete(cache.entries, evicted.uri)
+		cache.size -= evicted.size
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"testing"
+)
+
+func TestLinkCache(t *testing.T) {
+	type put struct {
+		uri           string
+		size          uint64
+		interlanguage bool
+	}
+	type get struct {
+		uri           string
+		interlanguage bool
+		wantHit       bool
+	}
+	tests := []struct {
+		name     string
+		maxBytes uint64
+		puts     []put
+		gets     []get
+		wantLen  int
+	}{
+		{
+			name:     "answer cached links",
+			maxBytes: 100,
+			puts:     []put{{uri: "/wiki/A", size: 10}, {uri: "/wiki/B", size: 10}},
+			gets:     []get{{uri: "/wiki/A", wantHit: true}, {uri: "/wiki/B", wantHit: true}, {uri: "/wiki/C"}},
+			wantLen:  2,
+		},
+		{
+			name:     "evict least recently used links",
+			maxBytes: 25,
+			puts:     []put{{uri: "/wiki/A", size: 10}, {uri: "/wiki/B", size: 10}, {uri: "/wiki/C", size: 10}},
+			gets:     []get{{uri: "/wiki/A"}, {uri: "/wiki/B", wantHit: true}, {uri: "/wiki/C", wantHit: true}},
+			wantLen:  2,
+		},
+		{
+			name:     "never cache links exceeding the limit",
+			maxBytes: 5,
+			puts:     []put{{uri: "/wiki/A", size: 10}},
+			gets:     []get{{uri: "/wiki/A"}},
+		},
+		{
+			name:     "miss links collected without interlanguage links",
+			maxBytes: 100,
+			puts:     []put{{uri: "/wiki/A", size: 10}},
+			gets:     []get{{uri: "/wiki/A", interlanguage: true}, {uri: "/wiki/A", wantHit: true}},
+			wantLen:  1,
+		},
+		{
+			name:     "replace links collected with fewer settings",
+			maxBytes: 100,
+			puts:     []put{{uri: "/wiki/A", size: 10}, {uri: "/wiki/A", size: 20, interlanguage: true}, {uri: "/wiki/A", size: 10}},
+			gets:     []get{{uri: "/wiki/A", interlanguage: true, wantHit: true}, {uri: "/wiki/A", wantHit: true}},
+			wantLen:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewLinkCache(tt.maxBytes)
+			for _, p := range tt.puts {
+				cache.put(p.uri, &pageLinks{size: p.size, interlanguage: p.interlanguage})
+			}
+
+			var wantHits, wantMisses uint64
+			for _, g := range tt.gets {
+				if _, hit := cache.get(g.uri, g.interlanguage, false); hit != g.wantHit {
+					t.Errorf("get(%s, interlanguage %v) hit = %v, want %v", g.uri, g.interlanguage, hit, g.wantHit)
+				}
+				if g.wantHit {
+					wantHits++
+				} else {
+					wantMisses++
+				}
+			}
+			if hits, misses := cache.Stats(); hits != wantHits || misses != wantMisses {
+				t.Errorf("Stats() = %d hits and %d misses, want %d hits and %d misses", hits, misses, wantHits, wantMisses)
+			}
+			if cache.Len() != tt.wantLen {
+				t.Errorf("Len() = %d, want %d", cache.Len(), tt.wantLen)
+			}
+			if cache.size > tt.maxBytes {
+				t.Errorf("cached %d bytes, limit %d", cache.size, tt.maxBytes)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"golang.org/x/net/html"
+	"regexp"
+	"strings"
+)
+
+const (
+	maxSnippetLength = 300
+)
+
+var (
+	blockElements = map[string]bool{
+		"p":          true,
+		"li":         true,
+		"dd":         true,
+		"dt":         true,
+		"td":         true,
+		"th":         true,
+		"caption":    true,
+		"blockquote": true,
+		"figcaption": true,
+	}
+	headingElements = map[string]bool{
+		"h2": true,
+		"h3": true,
+		"h4": true,
+		"h5": true,
+		"h6": true,
+	}
+	// sentences may end with closing quotes and citation markers like ."[8][9]
+	sentenceEndRegex = regexp.MustCompile(`[.!?]["'”’)]*(\[[^\]]*\])*\s`)
+	whitespaceRegex  = regexp.MustCompile(`\s+`)
+)
+
+// LinkContext describes where on a page the link to another page appears
+type LinkContext struct {
+	AnchorText string
+	// Section is the heading of the section containing the link, empty for the lead section
+	Section string
+	// Snippet is the sentence containing the link, empty if the link is not part of running text
+	Snippet string
+}
+
+type textBlock struct {
+	depth int
+	text  strings.Builder
+	links []*pendingLink
+}
+
+type pendingLink struct {
+	id         pageID
+	capture    bool
+	section    string
+	block      *textBlock
+	start      int
+	end        int
+	anchorText strings.Builder
+}
+
+// linkContextCollector tracks the text surrounding the links while the page is tokenized.
+// Depths are the size of the token stack of the parser to match start and end tags.
+type linkContextCollector struct {
+	contexts     map[pageID]LinkContext
+	section      string
+	heading      *strings.Builder
+	headingDepth int
+	skipDepth    int
+	blocks       []*textBlock
+	anchor       *pendingLink
+	anchorDepth  int
+}
+
+func newLinkContextCollector(contexts map[pageID]LinkContext) *linkContextCollector {
+	return &linkContextCollector{contexts: contexts}
+}
+
+// startTag has to be called after the token was pushed to the stack of the parser
+func (collector *linkContextCollector) startTag(token html.Token, depth int) {
+	switch {
+	case collector.skipDepth == 0 && hasClass(token, "mw-editsection"):
+		collector.skipDepth = depth
+	case headingElements[token.Data]:
+		collector.heading = &strings.Builder{}
+		collector.headingDepth = depth
+	case blockElements[token.Data]:
+		collector.blocks = append(collector.blocks, &textBlock{depth: depth})
+	case token.Data == "a":
+		collector.anchor = &pendingLink{section: collector.section, block: collector.currentBlock()}
+		if collector.anchor.block != nil {
+			collector.anchor.start = collector.anchor.block.text.Len()
+		}
+		collector.anchorDepth = depth
+	}
+}
+
+// link marks the currently open anchor as link to a newly discovered page
+func (collector *linkContextCollector) link(id pageID) {
+	if collector.anchor != nil {
+		collector.anchor.id = id
+		collector.anchor.capture = true
+	}
+}
+
+// endTag has to be called before the token is popped from the stack of the parser
+func (collector *linkContextCollector) endTag(token html.Token, depth int) {
+	switch {
+	case depth == collector.skipDepth:
+		collector.skipDepth = 0
+	case collector.heading != nil && depth == collector.headingDepth:
+		collector.section = normalizeText(collector.heading.String())
+		collector.heading = nil
+	case collector.anchor != nil && depth == collector.anchorDepth:
+		collector.closeAnchor()
+	case len(collector.blocks) > 0 && depth == collector.currentBlock().depth:
+		collector.closeBlock()
+	}
+}
+
+func (collector *linkContextCollector) text(text string) {
+	if collector.skipDepth != 0 {
+		return
+	}
+
+	if collector.heading != nil {
+		collector.heading.WriteString(text)
+		return
+	}
+
+	if block := collector.currentBlock(); block != nil {
+		block.text.WriteString(text)
+	}
+
+	if collector.anchor != nil {
+		collector.anchor.anchorText.WriteString(text)
+	}
+}
+
+// finish resolves all links of blocks which were not closed until the end of the content
+func (collector *linkContextCollector) finish() {
+	if collector.anchor != nil {
+		collector.closeAnchor()
+	}
+	for len(collector.blocks) > 0 {
+		collector.closeBlock()
+	}
+}
+
+func (collector *linkContextCollector) currentBlock() *textBlock {
+	if len(collector.blocks) == 0 {
+		return nil
+	}
+	return collector.blocks[len(collector.blocks)-1]
+}
+
+func (collector *linkContextCollector) closeAnchor() {
+	anchor := collector.anchor
+	collector.anchor = nil
+
+	if !anchor.capture {
+		return
+	}
+
+	if anchor.block == nil {
+		collector.resolve(anchor, "")
+		return
+	}
+
+	anchor.end = anchor.block.text.Len()
+	anchor.block.links = append(anchor.block.links, anchor)
+}
+
+// closeBlock resolves the snippets of all links in the block and hands the text over to the enclosing block
+func (collector *linkContextCollector) closeBlock() {
+	block := collector.currentBlock()
+	collector.blocks = collector.blocks[:len(collector.blocks)-1]
+
+	text := block.text.String()
+	for _, link := range block.links {
+		collector.resolve(link, sentenceAround(text, link.start, link.end))
+	}
+
+	if parent := collector.currentBlock(); parent != nil {
+		parent.text.WriteString(text)
+	}
+}
+
+func (collector *linkContextCollector) resolve(link *pendingLink, snippet string) {
+	collector.contexts[link.id] = LinkContext{
+		AnchorText: normalizeText(link.anchorText.String()),
+		Section:    link.section,
+		Snippet:    snippet,
+	}
+}
+
+// sentenceAround extracts the sentence of text containing the range start to end
+func sentenceAround(text string, start, end int) string {
+	begin := 0
+	for _, match := range sentenceEndRegex.FindAllStringIndex(text[:start], -1) {
+		begin = match[1]
+	}
+
+	stop := len(text)
+	if match := sentenceEndRegex.FindStringIndex(text[end:]); match != nil {
+		stop = end + match[1]
+	}
+
+	snippet := []rune(normalizeText(text[begin:stop]))
+	if len(snippet) > maxSnippetLength {
+		return string(snippet[:maxSnippetLength]) + "…"
+	}
+	return string(snippet)
+}
+
+func normalizeText(text string) string {
+	return strings.TrimSpace(whitespaceRegex.ReplaceAllString(text, " "))
+}
+
+func hasClass(token html.Token, class string) bool {
+	for _, attr := range token.Attr {
+		if attr.Key != "class" {
+			continue
+		}
+		for _, value := range strings.Fields(attr.Val) {
+			if value == class {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_extractLinksFromContent_linkContext(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		wantContexts map[string]LinkContext
+	}{
+		{
+			name: "link in lead section",
+			body: `<div id="bodyContent"><p>Times New Roman is a serif typeface. It was commissioned by
+<a href="/wiki/The_Times">the British newspaper <i>The Times</i></a> in 1931. It was designed by Victor Lardent.</p></div>`,
+			wantContexts: map[string]LinkContext{
+				"The_Times": {
+					AnchorText: "the British newspaper The Times",
+					Section:    "",
+					Snippet:    "It was commissioned by the British newspaper The Times in 1931.",
+				},
+			},
+		},
+		{
+			name: "links in sections and lists",
+			body: `<div id="bodyContent"><h2><span class="mw-headline" id="History">History</span><span class="mw-editsection">[<a href="/w/index.php?action=edit">edit</a>]</span></h2>
+<p>First sentence! The font is a <a href="/wiki/Serif">serif</a> font</p>
+<h3><span class="mw-headline">See also</span></h3><ul><li><a href="/wiki/Georgia_(typeface)">Georgia</a></li></ul>
+<p>Again <a href="/wiki/Serif">serif</a>.</p></div>`,
+			wantContexts: map[string]LinkContext{
+				"Serif": {
+					AnchorText: "serif",
+					Section:    "History",
+					Snippet:    "The font is a serif font",
+				},
+				"Georgia_(typeface)": {
+					AnchorText: "Georgia",
+					Section:    "See also",
+					Snippet:    "Georgia",
+				},
+			},
+		},
+		{
+			name: "link outside of running text",
+			body: `<div id="bodyContent"><div class="hatnote"><a href="/wiki/Times_Roman">Times Roman</a></div></div>`,
+			wantContexts: map[string]LinkContext{
+				"Times_Roman": {
+					AnchorText: "Times Roman",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			titles := newTitleInterner()
+			linkContexts := make(map[pageID]LinkContext)
+			if _, err := extractLinksFromContent(strings.NewReader(tt.body), linkScope{site: builtinSiteProfiles[0]}, titles, linkContexts); err != nil {
+				t.Fatalf("extractLinksFromContent() error = %v", err)
+			}
+
+			gotContexts := make(map[string]LinkContext)
+			for id, linkContext := range linkContexts {
+				gotContexts[titles.Title(id)] = linkContext
+			}
+
+			if !reflect.DeepEqual(gotContexts, tt.wantContexts) {
+				t.Errorf("extractLinksFromContent() link contexts = %+v, want %+v", gotContexts, tt.wantContexts)
+			}
+		})
+	}
+}
+
+func Test_sentenceAround(t *testing.T) {
+	type args struct {
+		text  string
+		start int
+		end   int
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "single sentence",
+			args: args{text: "A link here", start: 2, end: 6},
+			want: "A link here",
+		},
+		{
+			name: "sentence in the middle",
+			args: args{text: "First one. Second link? Third.", start: 18, end: 22},
+			want: "Second link?",
+		},
+		{
+			name: "abbreviation without trailing whitespace",
+			args: args{text: "Version 1.5 links\nhere. Next.", start: 12, end: 17},
+			want: "Version 1.5 links here.",
+		},
+		{
+			name: "sentence ending with quote and citations",
+			args: args{text: `He said "no."[8][32] Morison liked links.[34] Next one.`, start: 31, end: 36},
+			want: "Morison liked links.[34]",
+		},
+		{
+			name: "long sentence is truncated",
+			args: args{text: strings.Repeat("a", maxSnippetLength+10), start: 0, end: 1},
+			want: strings.Repeat("a", maxSnippetLength) + "…",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sentenceAround(tt.args.text, tt.args.start, tt.args.end); got != tt.want {
+				t.Errorf("sentenceAround() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"golang.org/x/net/html"
+	"io"
+	"strings"
+)
+
+const (
+	// ContentRegion is the region of links within the content root of the page
+	ContentRegion = "content"
+	pageRegion    = "page"
+
+	beforeContentRoot = "before the content root"
+)
+
+var (
+	// voidElements never have a closing tag, outside of the content root they are not pushed to the stack of open elements
+	voidElements = map[string]bool{
+		"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true, "input": true,
+		"link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+	}
+)
+
+// LinkDecision explains whether a single anchor of a page is followed by the search
+type LinkDecision struct {
+	Href       string
+	AnchorText string
+	// Title is the display title of the linked article, empty if the link is no article link
+	Title string
+	// URI is the full URI of the linked article, empty if the link is no article link
+	URI       string
+	Kind      LinkKind
+	Namespace string
+	// Region is ContentRegion for links in the content root, otherwise the id of the closest enclosing element with an id
+	Region string
+	// Section is the heading of the section of the content containing the link, empty for the lead section
+	Section string
+	Kept    bool
+	Reason  string
+}
+
+type openElement struct {
+	name          string
+	id            string
+	interlanguage bool
+}
+
+// linkInspector replays the decisions of extractLinksFromContent for every anchor of the page
+type linkInspector struct {
+	page          PageReference
+	scope         linkScope
+	stack         []openElement
+	contentDepth  int
+	contentClosed bool
+	heading       *strings.Builder
+	headingDepth  int
+	section       string
+	skipDepth     int
+	anchor        *LinkDecision
+	anchorDepth   int
+	seen          map[string]bool
+	decisions     []LinkDecision
+	// parseErr is the error the search fails to parse the page with, the search follows none of its links then
+	parseErr error
+}
+
+// InspectLinks explains for every anchor of the page whether the search follows it and why.
+// The decisions are the same as made while searching, kept links are the pages discovered on the page.
+// Within the content root every element has to be closed in order like the search expects it,
+// otherwise the page fails to parse and no link is kept.
+func InspectLinks(body io.Reader, page PageReference, interlanguage bool) (decisions []LinkDecision, err error) {
+	inspector := &linkInspector{
+		page: page,
+		scope: linkScope{
+			site:          page.Site,
+			startWiki:     page.WikiBase,
+			interlanguage: interlanguage,
+		},
+		// the page itself is already known to the search when its links are extracted
+		seen: map[string]bool{page.Title: true},
+	}
+
+	tokenizer := html.NewTokenizer(body)
+	for tokenizer.Next() != html.ErrorToken {
+		token := tokenizer.Token()
+		switch token.Type {
+		case html.StartTagToken:
+			// the search does not know about void elements and expects them to be closed in the content root
+			lenient := !inspector.strict()
+			inspector.startTag(token)
+			if lenient && voidElements[token.Data] {
+				inspector.endTag(token.Data)
+			}
+		case html.SelfClosingTagToken:
+			inspector.startTag(token)
+			inspector.endTag(token.Data)
+		case html.EndTagToken:
+			inspector.endTag(token.Data)
+		case html.TextToken:
+			inspector.text(token.Data)
+		}
+	}
+
+	if err = tokenizer.Err(); err == io.EOF {
+		err = nil
+	}
+	if inspector.contentDepth == 0 {
+		inspector.contentRootMissing()
+	}
+	return inspector.decisions, err
+}
+
+func (inspector *linkInspector) startTag(token html.Token) {
+	element := openElement{name: token.Data}
+	for _, attr := range token.Attr {
+		if attr.Key == "id" {
+			element.id = attr.Val
+		}
+	}
+	element.interlanguage = token.Data == "li" && hasClass(token, "interlanguage-link")
+	inspector.stack = append(inspector.stack, element)
+	depth := len(inspector.stack)
+
+	switch {
+	case inspector.contentDepth == 0 && inspector.isContentRoot(token):
+		inspector.contentDepth = depth
+	case inspector.skipDepth == 0 && hasClass(token, "mw-editsection"):
+		inspector.skipDepth = depth
+	case inspector.inContent() && headingElements[token.Data]:
+		inspector.heading = &strings.Builder{}
+		inspector.headingDepth = depth
+	case token.Data == "a":
+		inspector.anchor = inspector.decide(token)
+		inspector.anchorDepth = depth
+	}
+}
+
+func (inspector *linkInspector) endTag(name string) {
+	if inspector.strict() && inspector.stack[len(inspector.stack)-1].name != name {
+		inspector.fail(errUnmatchedEndTag)
+	}
+	for depth := len(inspector.stack); depth > 0; depth-- {
+		if inspector.stack[depth-1].name != name {
+			continue
+		}
+		// close all elements which were left open within the closed element
+		for len(inspector.stack) >= depth {
+			inspector.pop()
+		}
+		return
+	}
+}
+
+func (inspector *linkInspector) pop() {
+	depth := len(inspector.stack)
+	inspector.stack = inspector.stack[:depth-1]
+
+	switch depth {
+	case inspector.anchorDepth:
+		if inspector.anchor != nil {
+			inspector.anchor.AnchorText = normalizeText(inspector.anchor.AnchorText)
+			inspector.decisions = append(inspector.decisions, *inspector.anchor)
+			inspector.anchor = nil
+		}
+		inspector.anchorDepth = 0
+	case inspector.headingDepth:
+		if inspector.heading != nil {
+			inspector.section = normalizeText(inspector.heading.String())
+			inspector.heading = nil
+		}
+		inspector.headingDepth = 0
+	case inspector.skipDepth:
+		inspector.skipDepth = 0
+	case inspector.contentDepth:
+		inspector.contentClosed = true
+	}
+}
+
+func (inspector *linkInspector) text(text string) {
+	if inspector.anchor != nil {
+		inspector.anchor.AnchorText += text
+	}
+	if inspector.heading != nil && inspector.skipDepth == 0 {
+		inspector.heading.WriteString(text)
+	}
+}
+
+func (inspector *linkInspector) isContentRoot(token html.Token) bool {
+	selector := inspector.scope.site.ContentRoot
+	if token.Data != selector.Element {
+		return false
+	}
+	if selector.Attribute == "class" {
+		return hasClass(token, selector.Value)
+	}
+	for _, attr := range token.Attr {
+		if attr.Key == selector.Attribute && attr.Val == selector.Value {
+			return true
+		}
+	}
+	return false
+}
+
+func (inspector *linkInspector) inContent() bool {
+	return inspector.contentDepth > 0 && !inspector.contentClosed
+}
+
+// strict is true as long as the elements are tracked like the search does i.e. within the content root of a page which parses
+func (inspector *linkInspector) strict() bool {
+	return inspector.inContent() && inspector.parseErr == nil
+}
+
+// fail drops all links kept so far as the search follows no link of a page which fails to parse
+func (inspector *linkInspector) fail(err error) {
+	inspector.parseErr = err
+	for idx := range inspector.decisions {
+		inspector.dropUnparsed(&inspector.decisions[idx])
+	}
+	if inspector.anchor != nil {
+		inspector.dropUnparsed(inspector.anchor)
+	}
+}
+
+func (inspector *linkInspector) dropUnparsed(decision *LinkDecision) {
+	if decision.Kept {
+		decision.Kept = false
+		decision.Reason = "page fails to parse: " + inspector.parseErr.Error()
+	}
+}
+
+// contentRootMissing explains the links of a page without content root, the search fails to parse such pages
+func (inspector *linkInspector) contentRootMissing() {
+	selector := inspector.scope.site.ContentRoot
+	reason := elementNotFoundError(selector.Element, selector.Attribute, selector.Value).Error()
+	for idx := range inspector.decisions {
+		if inspector.decisions[idx].Reason == beforeContentRoot {
+			inspector.decisions[idx].Reason = reason
+		}
+	}
+}
+
+// decide mirrors the filters of extractLinksFromContent and extractInterlanguageLinks
+func (inspector *linkInspector) decide(token html.Token) *LinkDecision {
+	decision := &LinkDecision{Region: inspector.region()}
+	if inspector.inContent() {
+		decision.Section = inspector.section
+	}
+
+	hasHref := false
+	for _, attr := range token.Attr {
+		if attr.Key == "href" {
+			decision.Href = attr.Val
+			hasHref = true
+		}
+	}
+
+	switch {
+	case !hasHref:
+		decision.Reason = "anchor without href"
+	case inspector.inContent():
+		title, namespace, reason := inspector.scope.site.classifyHref(decision.Href)
+		decision.Namespace = namespace
+		if reason != "" {
+			decision.Reason = reason
+			break
+		}
+		inspector.keep(decision, title, ArticleLink)
+	case inspector.inInterlanguageList():
+		if !inspector.scope.interlanguage {
+			decision.Reason = "interlanguage links are disabled"
+			break
+		}
+		key, ok := inspector.scope.interlanguageKey(decision.Href)
+		if !ok {
+			decision.Reason = "no article of another language edition"
+			break
+		}
+		inspector.keep(decision, key, InterlanguageLink)
+	case inspector.contentDepth == 0:
+		decision.Reason = beforeContentRoot
+	default:
+		decision.Reason = "after the content root"
+	}
+	return decision
+}
+
+func (inspector *linkInspector) keep(decision *LinkDecision, key string, kind LinkKind) {
+	wiki, title := splitQualifiedTitle(key)
+	if wiki == "" {
+		wiki = inspector.page.WikiBase
+	}
+	decision.Title = displayTitle(key)
+	decision.URI = inspector.scope.site.articleURI(wiki, title)
+	decision.Kind = kind
+
+	if inspector.seen[key] {
+		decision.Reason = "page is already known"
+		return
+	}
+	inspector.seen[key] = true
+	if inspector.parseErr != nil {
+		decision.Reason = "page fails to parse: " + inspector.parseErr.Error()
+		return
+	}
+	decision.Kept = true
+	if kind == InterlanguageLink {
+		decision.Reason = "interlanguage link"
+	} else {
+		decision.Reason = "article link"
+	}
+}
+
+// inInterlanguageList checks whether the anchor is part of the language list after the content root
+func (inspector *linkInspector) inInterlanguageList() bool {
+	if !inspector.contentClosed {
+		return false
+	}
+	for _, element := range inspector.stack {
+		if element.interlanguage {
+			return true
+		}
+	}
+	return false
+}
+
+func (inspector *linkInspector) region() string {
+	if inspector.inContent() {
+		return ContentRegion
+	}
+	for idx := len(inspector.stack) - 1; idx >= 0; idx-- {
+		if id := inspector.stack[idx].id; id != "" {
+			return id
+		}
+	}
+	return pageRegion
+}
@@ -0,0 +1,165 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInspectLinks(t *testing.T) {
+	page := PageReference{WikiBase: "https://de.wikipedia.org", Title: "Schriftart", Site: builtinSiteProfiles[0]}
+	body := `<html><body><div id="top"><a href="/wiki/Hauptseite">Hauptseite</a></div>
+<div id="bodyContent"><p>Eine <a href="/wiki/Serife#Geschichte">Serife</a> ist <a href="/wiki/Serife">kein</a> Teil der
+<a href="/wiki/Schriftart">Schriftart</a>.</p>
+<h2><span class="mw-headline">Weblinks</span><span class="mw-editsection"><a href="/w/index.php?title=Schriftart&amp;action=edit">Bearbeiten</a></span></h2>
+<img src="/static/logo.png"/><a href="/wiki/File:Beispiel.svg">Datei</a> <a href="https://example.com">extern</a> <a>leer</a></div>
+<div id="p-lang"><ul><li class="interlanguage-link"><a href="https://en.wikipedia.org/wiki/Typeface">English</a></li></ul></div>
+</body></html>`
+
+	tests := []struct {
+		name          string
+		interlanguage bool
+		want          []LinkDecision
+	}{
+		{
+			name:          "explain decisions with interlanguage links",
+			interlanguage: true,
+			want: []LinkDecision{
+				{Href: "/wiki/Hauptseite", AnchorText: "Hauptseite", Region: "top", Reason: "before the content root"},
+				{Href: "/wiki/Serife#Geschichte", AnchorText: "Serife", Title: "Serife", URI: "https://de.wikipedia.org/wiki/Serife", Region: ContentRegion, Kept: true, Reason: "article link"},
+				{Href: "/wiki/Serife", AnchorText: "kein", Title: "Serife", URI: "https://de.wikipedia.org/wiki/Serife", Region: ContentRegion, Reason: "page is already known"},
+				{Href: "/wiki/Schriftart", AnchorText: "Schriftart", Title: "Schriftart", URI: "https://de.wikipedia.org/wiki/Schriftart", Region: ContentRegion, Reason: "page is already known"},
+				{Href: "/w/index.php?title=Schriftart&action=edit", AnchorText: "Bearbeiten", Region: ContentRegion, Reason: "no article path"},
+				{Href: "/wiki/File:Beispiel.svg", AnchorText: "Datei", Namespace: "File", Region: ContentRegion, Section: "Weblinks", Reason: "non-article namespace File"},
+				{Href: "https://example.com", AnchorText: "extern", Region: ContentRegion, Section: "Weblinks", Reason: "no article path"},
+				{AnchorText: "leer", Region: ContentRegion, Section: "Weblinks", Reason: "anchor without href"},
+				{Href: "https://en.wikipedia.org/wiki/Typeface", AnchorText: "English", Title: "en:Typeface", URI: "https://en.wikipedia.org/wiki/Typeface", Kind: InterlanguageLink, Region: "p-lang", Kept: true, Reason: "interlanguage link"},
+			},
+		},
+		{
+			name: "drop interlanguage links if disabled",
+			want: []LinkDecision{
+				{Href: "https://en.wikipedia.org/wiki/Typeface", AnchorText: "English", Region: "p-lang", Reason: "interlanguage links are disabled"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := InspectLinks(strings.NewReader(body), page, tt.interlanguage)
+			if err != nil {
+				t.Fatalf("InspectLinks() error = %v", err)
+			}
+			if !tt.interlanguage {
+				got = got[len(got)-1:]
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InspectLinks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInspectLinks_MatchesExtraction(t *testing.T) {
+	tests := []struct {
+		name          string
+		fileName      string
+		body          string
+		title         string
+		interlanguage bool
+		wantReasons   []string
+	}{
+		{
+			name:     "Times New Roman article",
+			fileName: "../../assets/test-data/times_new_roman_article.html",
+			title:    "Times_New_Roman",
+		},
+		{
+			name:          "Manduca Jordani article with interlanguage links",
+			fileName:      "../../assets/test-data/manduca_jordani_article.html",
+			title:         "Manduca_jordani",
+			interlanguage: true,
+		},
+		{
+			name:        "unclosed void element",
+			body:        `<div id="bodyContent"><p>foo<br>bar <a href="/wiki/Alpha">Alpha</a></p><p><a href="/wiki/Beta">Beta</a></p></div>`,
+			title:       "Schriftart",
+			wantReasons: []string{"page fails to parse: " + errUnmatchedEndTag.Error(), "page fails to parse: " + errUnmatchedEndTag.Error()},
+		},
+		{
+			name:        "unclosed element before a kept link",
+			body:        `<div id="bodyContent"><p><a href="/wiki/Alpha">Alpha</a><b>bold</p><a href="/wiki/Alpha">again</a><a href="/wiki/Beta">Beta</a></div>`,
+			title:       "Schriftart",
+			wantReasons: []string{"page fails to parse: " + errUnmatchedEndTag.Error(), "page is already known", "page fails to parse: " + errUnmatchedEndTag.Error()},
+		},
+		{
+			name:        "missing content root",
+			body:        `<div id="content"><a href="/wiki/Alpha">Alpha</a></div>`,
+			title:       "Schriftart",
+			wantReasons: []string{"requested element with type div and selector id=bodyContent not found"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			open := func() io.Reader {
+				if tt.body != "" {
+					return strings.NewReader(tt.body)
+				}
+				return MustOpen(tt.fileName)
+			}
+
+			page := PageReference{WikiBase: "https://en.wikipedia.org", Title: tt.title, Site: builtinSiteProfiles[0]}
+			decisions, err := InspectLinks(open(), page, tt.interlanguage)
+			if err != nil {
+				t.Fatalf("InspectLinks() error = %v", err)
+			}
+
+			titles := newTitleInterner()
+			titles.Intern(tt.title)
+			scope := linkScope{site: page.Site, startWiki: page.WikiBase, interlanguage: tt.interlanguage}
+			// the search follows no link of a page which fails to parse
+			links, err := extractLinksFromContent(open(), scope, titles, nil)
+			if err != nil {
+				links = nil
+			}
+
+			var kept []string
+			for _, decision := range decisions {
+				if decision.Kept {
+					kept = append(kept, decision.URI)
+				}
+			}
+			var extracted []string
+			for _, link := range links {
+				extracted = append(extracted, (WikiCrawler{site: page.Site, wikiBaseDomain: page.WikiBase}).titleURI(titles.Title(link.id)))
+			}
+			if !reflect.DeepEqual(kept, extracted) {
+				t.Errorf("InspectLinks() kept %d links, extractLinksFromContent() found %d", len(kept), len(extracted))
+			}
+
+			if tt.wantReasons != nil {
+				var reasons []string
+				for _, decision := range decisions {
+					reasons = append(reasons, decision.Reason)
+				}
+				if !reflect.DeepEqual(reasons, tt.wantReasons) {
+					t.Errorf("InspectLinks() reasons = %q, want %q", reasons, tt.wantReasons)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"strings"
+	"sync"
+)
+
+// pageURIFormatter materialises the full page URI of an interned page
+type pageURIFormatter func(pageID) string
+
+// PathPage is a single page on the resolved path
+type PathPage struct {
+	Title string
+	URI   string
+	// Link is the context of the link on the previous page leading to this page, nil if it was not captured
+	Link *LinkContext
+	// Interlanguage is set if the page was reached by an interlanguage link from the previous page
+	Interlanguage bool
+}
+
+// TraversalResult is the outcome of a search, the zero value is the result of a search which did not find the target
+type TraversalResult struct {
+	path         []pageID
+	titles       *titleInterner
+	pageURI      pageURIFormatter
+	linkContexts map[pageID]LinkContext
+	// interlanguageEdges marks the pages discovered by an interlanguage link
+	interlanguageEdges map[pageID]bool
+}
+
+// Found reports whether the target was reached
+func (tr TraversalResult) Found() bool {
+	return len(tr.path) > 0
+}
+
+// Path returns the pages on the resolved path ordered from the start page to the target page, nil if the target was not found
+func (tr TraversalResult) Path() (path []PathPage) {
+	for idx, id := range tr.path {
+		page := PathPage{
+			Title:         displayTitle(tr.titles.Title(id)),
+			URI:           tr.pageURI(id),
+			Interlanguage: tr.interlanguageEdges[id],
+		}
+		if linkContext, ok := tr.linkContexts[id]; ok && idx > 0 {
+			page.Link = &linkContext
+		}
+		path = append(path, page)
+	}
+	return
+}
+
+// Hops returns the number of links followed from the start page to the target page
+func (tr TraversalResult) Hops() int {
+	if !tr.Found() {
+		return 0
+	}
+	return len(tr.path) - 1
+}
+
+// VisitedPages returns the URIs of the pages on the resolved path beginning with the target page.
+//
+// Deprecated: use Path which is ordered from the start page to the target page.
+func (tr TraversalResult) VisitedPages() (visitedPages []string) {
+	for i := len(tr.path) - 1; i >= 0; i-- {
+		visitedPages = append(visitedPages, tr.pageURI(tr.path[i]))
+	}
+	return
+}
+
+// SearchProgress is a snapshot of the state of a search
+type SearchProgress struct {
+	// Depth is the BFS level currently processed i.e. the distance of the processed pages to the start page
+	Depth uint16
+	// FrontierSize is the number of pages of the current level
+	FrontierSize uint64
+	// ProcessedPages is the number of pages of the current level which are already processed
+	ProcessedPages   uint64
+	NextFrontierSize uint64
+	FetchedPages     uint
+	// CachedPages is the number of pages whose links were answered by the link cache instead of retrieving them
+	CachedPages     uint
+	DiscoveredPages int
+}
+
+// progressTracker shares the progress of a running search with other goroutines
+type progressTracker struct {
+	lock     sync.Mutex
+	progress SearchProgress
+}
+
+func (tracker *progressTracker) get() SearchProgress {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+	return tracker.progress
+}
+
+func (tracker *progressTracker) set(progress SearchProgress) {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+	tracker.progress = progress
+}
+
+// RuledOutHops returns the number of hops up to which no path to the target exists.
+// As all levels before the current one are completely processed every page in reach of Depth hops is already discovered.
+func (progress SearchProgress) RuledOutHops() uint16 {
+	return progress.Depth
+}
+
+// displayTitle converts the interned title to the title as displayed by the wiki.
+// Titles of other wikis are prefixed with the subdomain of their wiki like interwiki links e.g. de:Times New Roman.
+func displayTitle(key string) string {
+	wiki, title := splitQualifiedTitle(key)
+	title = strings.Replace(title, "_", " ", -1)
+	if wiki == "" {
+		return title
+	}
+	return strings.SplitN(wikiHost(wiki), ".", 2)[0] + ":" + title
+}
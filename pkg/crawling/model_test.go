@@ -0,0 +1,135 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTraversalResult_VisitedPages(t *testing.T) {
+	titles := newTitleInterner()
+	timesNewRoman, _ := titles.Intern("Times_New_Roman")
+	theTimes, _ := titles.Intern("The_Times")
+	pageURI := func(id pageID) string {
+		return "https://en.wikipedia.org/wiki/" + titles.Title(id)
+	}
+
+	type fields struct {
+		path []pageID
+	}
+	tests := []struct {
+		name             string
+		fields           fields
+		wantVisitedPages []string
+	}{
+		{
+			name: "get visited pages if path is empty",
+			fields: fields{
+				path: nil,
+			},
+			wantVisitedPages: nil,
+		},
+		{
+			name: "get visited pages for single page path",
+			fields: fields{
+				path: []pageID{timesNewRoman},
+			},
+			wantVisitedPages: []string{"https://en.wikipedia.org/wiki/Times_New_Roman"},
+		},
+		{
+			name: "get visited pages for a path of pages",
+			fields: fields{
+				path: []pageID{timesNewRoman, theTimes},
+			},
+			wantVisitedPages: []string{
+				"https://en.wikipedia.org/wiki/The_Times",
+				"https://en.wikipedia.org/wiki/Times_New_Roman",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := TraversalResult{
+				path:    tt.fields.path,
+				titles:  titles,
+				pageURI: pageURI,
+			}
+			if gotVisitedPages := tr.VisitedPages(); !reflect.DeepEqual(gotVisitedPages, tt.wantVisitedPages) {
+				t.Errorf("VisitedPages() = %v, want %v", gotVisitedPages, tt.wantVisitedPages)
+			}
+		})
+	}
+}
+
+func TestTraversalResult_Path(t *testing.T) {
+	titles := newTitleInterner()
+	timesNewRoman, _ := titles.Intern("Times_New_Roman")
+	theTimes, _ := titles.Intern("The_Times")
+	pageURI := func(id pageID) string {
+		return "https://en.wikipedia.org/wiki/" + titles.Title(id)
+	}
+
+	type fields struct {
+		path []pageID
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		wantPath []PathPage
+		wantHops int
+	}{
+		{
+			name: "get path if path is empty",
+			fields: fields{
+				path: nil,
+			},
+			wantPath: nil,
+			wantHops: 0,
+		},
+		{
+			name: "get path beginning with start page",
+			fields: fields{
+				path: []pageID{timesNewRoman, theTimes},
+			},
+			wantPath: []PathPage{
+				{
+					Title: "Times New Roman",
+					URI:   "https://en.wikipedia.org/wiki/Times_New_Roman",
+				},
+				{
+					Title: "The Times",
+					URI:   "https://en.wikipedia.org/wiki/The_Times",
+				},
+			},
+			wantHops: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := TraversalResult{
+				path:    tt.fields.path,
+				titles:  titles,
+				pageURI: pageURI,
+			}
+			if gotPath := tr.Path(); !reflect.DeepEqual(gotPath, tt.wantPath) {
+				t.Errorf("Path() = %v, want %v", gotPath, tt.wantPath)
+			}
+			if gotHops := tr.Hops(); gotHops != tt.wantHops {
+				t.Errorf("Hops() = %d, want %d", gotHops, tt.wantHops)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"time"
+)
+
+// Observer is notified about every step of a search e.g. to render progress, to collect metrics or for custom logging.
+// All methods are called synchronously by the goroutine running the search and should return quickly.
+// Embed NopObserver to implement only some of the methods.
+type Observer interface {
+	// OnLevelStart is called before the first page of a BFS level is processed
+	OnLevelStart(event SearchEvent)
+	// OnLevelFinished is called after all pages of a BFS level are processed without finding the target
+	OnLevelFinished(event SearchEvent)
+	// OnPageFetched is called after a page was retrieved and parsed, duration covers both
+	OnPageFetched(pageURI string, duration time.Duration)
+	// OnLinksCached is called instead of OnPageFetched if the links of a page were answered by the link cache
+	OnLinksCached(pageURI string)
+	// OnLinksDiscovered is called for every processed page with the URIs of the pages discovered first on it
+	OnLinksDiscovered(pageURI string, links []string)
+	// OnPageProcessed is called after the links of a page are queued for the next level
+	OnPageProcessed(event SearchEvent)
+	// OnFetchError is called with a FetchError or a ParseError if a page could not be retrieved or parsed,
+	// the search continues without its links
+	OnFetchError(pageURI string, err error)
+	// OnPathFound is called when the target is discovered, the event carries the path
+	OnPathFound(event SearchEvent)
+	// OnSearchFinished is called when the search returns, err is the error returned by the search
+	// and hops is the length of the path if the target was found.
+	// The duration covers the search since it was started or resumed.
+	OnSearchFinished(hops int, duration time.Duration, err error)
+}
+
+// NopObserver ignores all steps of a search
+type NopObserver struct{}
+
+func (NopObserver) OnLevelStart(SearchEvent)                   {}
+func (NopObserver) OnLevelFinished(SearchEvent)                {}
+func (NopObserver) OnPageFetched(string, time.Duration)        {}
+func (NopObserver) OnLinksCached(string)                       {}
+func (NopObserver) OnLinksDiscovered(string, []string)         {}
+func (NopObserver) OnPageProcessed(SearchEvent)                {}
+func (NopObserver) OnFetchError(string, error)                 {}
+func (NopObserver) OnPathFound(SearchEvent)                    {}
+func (NopObserver) OnSearchFinished(int, time.Duration, error) {}
+
+func (crawler *WikiCrawler) notifyPageFetched(pageURI string, duration time.Duration) {
+	for _, observer := range crawler.observers {
+		observer.OnPageFetched(pageURI, duration)
+	}
+}
+
+func (crawler *WikiCrawler) notifyLinksCached(pageURI string) {
+	for _, observer := range crawler.observers {
+		observer.OnLinksCached(pageURI)
+	}
+}
+
+// notifyLinksDiscovered materialises the URIs of the discovered pages only if any observer is registered
+func (crawler *WikiCrawler) notifyLinksDiscovered(pageURI string, discoveredLinks []pageID) {
+	if len(crawler.observers) == 0 {
+		return
+	}
+
+	links := make([]string, 0, len(discoveredLinks))
+	for _, link := range discoveredLinks {
+		links = append(links, crawler.pageURI(link))
+	}
+	for _, observer := range crawler.observers {
+		observer.OnLinksDiscovered(pageURI, links)
+	}
+}
+
+func (crawler *WikiCrawler) notifyFetchError(pageURI string, err error) {
+	for _, observer := range crawler.observers {
+		observer.OnFetchError(pageURI, err)
+	}
+}
+
+func (crawler *WikiCrawler) notifySearchFinished(hops int, duration time.Duration, err error) {
+	for _, observer := range crawler.observers {
+		observer.OnSearchFinished(hops, duration, err)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultMaxHops is the depth of a search unless WithMaxHops is given
+const DefaultMaxHops uint16 = 20
+
+// Option configures a crawler when it is created
+type Option func(crawler *WikiCrawler)
+
+// WithMaxHops limits the search to paths of at most maxHops links
+func WithMaxHops(maxHops uint16) Option {
+	return func(crawler *WikiCrawler) {
+		crawler.maxHops = maxHops
+	}
+}
+
+// WithFrontierSpilling lets every BFS level keep at most memoryLimit bytes of queued pages in memory.
+// Exceeding pages are spilled to segment files in spillDirectory and streamed back when the next level is processed.
+// An empty spillDirectory falls back to the default temporary directory.
+func WithFrontierSpilling(spillDirectory string, memoryLimit uint64) Option {
+	return func(crawler *WikiCrawler) {
+		crawler.spillDirectory = spillDirectory
+		crawler.frontierMemoryLimit = memoryLimit
+	}
+}
+
+// WithLinkContext captures anchor text, section and sentence of the link every page was discovered by.
+// As the context is kept for every discovered page this considerably increases the memory usage.
+func WithLinkContext() Option {
+	return func(crawler *WikiCrawler) {
+		if crawler.linkContexts == nil {
+			crawler.linkContexts = make(map[pageID]LinkContext)
+		}
+	}
+}
+
+// WithInterlanguageLinks allows the search to hop between language editions of the wiki
+// by following the interlanguage links of every page.
+func WithInterlanguageLinks() Option {
+	return func(crawler *WikiCrawler) {
+		if crawler.interlanguageEdges == nil {
+			crawler.interlanguageEdges = make(map[pageID]bool)
+		}
+	}
+}
+
+// WithCheckpoints persists the search progress to path whenever interval elapsed since the last checkpoint.
+// An interval of 0 writes a checkpoint after every processed page.
+func WithCheckpoints(path string, interval time.Duration) Option {
+	return func(crawler *WikiCrawler) {
+		crawler.checkpointPath = path
+		crawler.checkpointInterval = interval
+		crawler.lastCheckpoint = time.Now()
+	}
+}
+
+// WithPageCache lets the crawler retrieve pages via the given cache shared with other crawlers
+func WithPageCache(cache *PageCache) Option {
+	return func(crawler *WikiCrawler) {
+		crawler.fetchPage = cache.Fetch
+	}
+}
+
+// WithLinkCache lets the crawler take the links of pages processed by other crawlers from the given cache
+// and share the links of the pages it retrieves
+func WithLinkCache(cache *LinkCache) Option {
+	return func(crawler *WikiCrawler) {
+		crawler.linkCache = cache
+	}
+}
+
+// WithHTTPClient lets the crawler retrieve pages directly with the given client instead of http.DefaultClient
+func WithHTTPClient(client *http.Client) Option {
+	return func(crawler *WikiCrawler) {
+		crawler.fetchPage = NewPageFetcher(client)
+	}
+}
+
+// WithTracer lets the crawler record spans of every search, of its BFS levels and of every processed page
+// with child spans for retrieving and parsing the page
+func WithTracer(tracer Tracer) Option {
+	return func(crawler *WikiCrawler) {
+		crawler.tracer = tracer
+	}
+}
+
+// WithObserver registers an observer notified about all searches of the crawler, observers are notified in the order of the options
+func WithObserver(observer Observer) Option {
+	return func(crawler *WikiCrawler) {
+		crawler.observers = append(crawler.observers, observer)
+	}
+}
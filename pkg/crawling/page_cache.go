@@ -0,0 +1,129 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+// PageCache keeps the raw HTML of recently fetched pages in memory to share them between crawlers.
+// If the cached pages exceed the memory limit the least recently used pages are evicted.
+// It is safe for concurrent use by multiple crawlers.
+type PageCache struct {
+	fetch    pageFetcher
+	maxBytes uint64
+	lock     sync.Mutex
+	size     uint64
+	entries  map[string]*list.Element
+	recency  *list.List
+	hits     uint64
+	misses   uint64
+}
+
+type cachedPage struct {
+	uri  string
+	body []byte
+}
+
+// NewPageCache creates a cache retrieving missing pages with fetch e.g. FetchPage.
+// Pages larger than maxBytes are never cached.
+func NewPageCache(fetch func(ctx context.Context, pageURI string) (io.ReadCloser, error), maxBytes uint64) *PageCache {
+	return &PageCache{
+		fetch:    fetch,
+		maxBytes: maxBytes,
+		entries:  make(map[string]*list.Element),
+		recency:  list.New(),
+	}
+}
+
+// Fetch returns the cached HTML of the page or retrieves and caches it.
+// Failed retrievals are not cached.
+func (cache *PageCache) Fetch(ctx context.Context, pageURI string) (io.ReadCloser, error) {
+	if body, ok := cache.get(pageURI); ok {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	reader, err := cache.fetch(ctx, pageURI)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.put(pageURI, body)
+	return ioutil.NopCloser(bytes.NewReader(body)), nil
+}
+
+// Len returns the number of cached pages
+func (cache *PageCache) Len() int {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.recency.Len()
+}
+
+// Stats returns the number of retrievals answered from the cache and the number of retrievals of missing pages
+func (cache *PageCache) Stats() (hits, misses uint64) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.hits, cache.misses
+}
+
+func (cache *PageCache) get(pageURI string) ([]byte, bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	element, ok := cache.entries[pageURI]
+	if !ok {
+		cache.misses++
+		return nil, false
+	}
+	cache.hits++
+	cache.recency.MoveToFront(element)
+	return element.Value.(*cachedPage).body, true
+}
+
+func (cache *PageCache) put(pageURI string, body []byte) {
+	if uint64(len(body)) > cache.maxBytes {
+		return
+	}
+
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	// another crawler might have fetched the same page meanwhile
+	if element, ok := cache.entries[pageURI]; ok {
+		cache.recency.MoveToFront(element)
+		return
+	}
+
+	cache.entries[pageURI] = cache.recency.PushFront(&cachedPage{uri: pageURI, body: body})
+	cache.size += uint64(len(body))
+
+	for cache.size > cache.maxBytes {
+		oldest := cache.recency.Back()
+		page := cache.recency.Remove(oldest).(*cachedPage)
+		delete(cache.entries, page.uri)
+		cache.size -= uint64(len(page.body))
+	}
+}
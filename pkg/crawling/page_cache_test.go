@@ -0,0 +1,103 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// countingFetcher serves the URI itself as page body and counts the retrievals per URI
+type countingFetcher map[string]int
+
+func (fetcher countingFetcher) fetch(_ context.Context, pageURI string) (io.ReadCloser, error) {
+	fetcher[pageURI]++
+	if strings.HasSuffix(pageURI, "Missing") {
+		return nil, errors.New("404 Not Found")
+	}
+	return ioutil.NopCloser(strings.NewReader(pageURI)), nil
+}
+
+func TestPageCache_Fetch(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxBytes    uint64
+		fetches     []string
+		wantFetches map[string]int
+		wantLen     int
+	}{
+		{
+			name:        "retrieve cached page only once",
+			maxBytes:    1024,
+			fetches:     []string{"/wiki/A", "/wiki/B", "/wiki/A"},
+			wantFetches: map[string]int{"/wiki/A": 1, "/wiki/B": 1},
+			wantLen:     2,
+		},
+		{
+			name:        "evict least recently used page",
+			maxBytes:    14,
+			fetches:     []string{"/wiki/A", "/wiki/B", "/wiki/A", "/wiki/C", "/wiki/A", "/wiki/B"},
+			wantFetches: map[string]int{"/wiki/A": 1, "/wiki/B": 2, "/wiki/C": 1},
+			wantLen:     2,
+		},
+		{
+			name:        "never cache pages exceeding the limit",
+			maxBytes:    4,
+			fetches:     []string{"/wiki/A", "/wiki/A"},
+			wantFetches: map[string]int{"/wiki/A": 2},
+		},
+		{
+			name:        "never cache failed retrievals",
+			maxBytes:    1024,
+			fetches:     []string{"/wiki/Missing", "/wiki/Missing"},
+			wantFetches: map[string]int{"/wiki/Missing": 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := countingFetcher{}
+			cache := NewPageCache(fetcher.fetch, tt.maxBytes)
+			for _, uri := range tt.fetches {
+				body, err := cache.Fetch(context.Background(), uri)
+				if err != nil {
+					continue
+				}
+				content, _ := ioutil.ReadAll(body)
+				if string(content) != uri {
+					t.Errorf("Fetch(%s) = %s", uri, content)
+				}
+			}
+
+			var retrievals uint64
+			for uri, want := range tt.wantFetches {
+				if fetcher[uri] != want {
+					t.Errorf("retrieved %s %d times, want %d", uri, fetcher[uri], want)
+				}
+				retrievals += uint64(want)
+			}
+			// every retrieval is a miss, all other fetches are answered from the cache
+			if hits, misses := cache.Stats(); misses != retrievals || hits != uint64(len(tt.fetches))-retrievals {
+				t.Errorf("Stats() = %d hits and %d misses, want %d hits and %d misses", hits, misses, uint64(len(tt.fetches))-retrievals, retrievals)
+			}
+			if cache.Len() != tt.wantLen {
+				t.Errorf("Len() = %d, want %d", cache.Len(), tt.wantLen)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	languageCodeRegex = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]+)*$`)
+)
+
+// PageReference identifies an article by the base URL of its wiki and its title as used in article paths
+type PageReference struct {
+	WikiBase string
+	Title    string
+	Site     SiteProfile
+}
+
+func (ref PageReference) URI() string {
+	return ref.Site.articleURI(ref.WikiBase, ref.Title)
+}
+
+// WikipediaBaseURL returns the base URL of the Wikipedia in the given language e.g. https://de.wikipedia.org for de
+func WikipediaBaseURL(language string) (baseURL string, err error) {
+	if !languageCodeRegex.MatchString(language) {
+		err = fmt.Errorf("%w %q", ErrInvalidLanguage, language)
+		return
+	}
+	baseURL = fmt.Sprintf("https://%s.wikipedia.org", language)
+	return
+}
+
+// ParseWikiBaseURL validates and normalizes the base URL of a wiki e.g. https://en.wiktionary.org
+func ParseWikiBaseURL(rawURL string) (baseURL string, err error) {
+	var parsed *url.URL
+	if parsed, err = url.Parse(rawURL); err != nil {
+		err = fmt.Errorf("%w %q: %v", ErrInvalidWikiURL, rawURL, err)
+		return
+	}
+
+	if !isHTTPURL(parsed) {
+		err = fmt.Errorf("%w %q: expected an http(s) URL like https://en.wikipedia.org", ErrInvalidWikiURL, rawURL)
+		return
+	}
+
+	baseURL = wikiBase(parsed)
+	return
+}
+
+// ResolvePages resolves the start and target page given either as full article URL or as plain title.
+// Titles are resolved relative to defaultWiki unless the other page is given as URL, then its wiki is used.
+// Both pages have to belong to the same wiki, the URL layout of the wiki is taken from the first matching site profile.
+func ResolvePages(start, target, defaultWiki string, profiles SiteProfiles) (startRef, targetRef PageReference, err error) {
+	if startRef, targetRef, err = ResolvePagesAcrossWikis(start, target, defaultWiki, profiles); err != nil {
+		return
+	}
+
+	if !SameWiki(startRef.WikiBase, targetRef.WikiBase) {
+		err = fmt.Errorf("%w: start page %s belongs to %s, target page %s to %s", ErrDifferentWikis, startRef.URI(), startRef.WikiBase, targetRef.URI(), targetRef.WikiBase)
+	}
+	return
+}
+
+// ResolvePage resolves a single page given either as full article URL or as title of defaultWiki
+func ResolvePage(page, defaultWiki string, profiles SiteProfiles) (ref PageReference, err error) {
+	if looksLikeURL(page) {
+		return parseArticleURL(page, profiles)
+	}
+	return titleReference(defaultWiki, page, profiles)
+}
+
+// ResolvePagesAcrossWikis resolves the start and target page like ResolvePages
+// but allows them to belong to different wikis e.g. to different language editions for searches via interlanguage links.
+func ResolvePagesAcrossWikis(start, target, defaultWiki string, profiles SiteProfiles) (startRef, targetRef PageReference, err error) {
+	startIsURL, targetIsURL := looksLikeURL(start), looksLikeURL(target)
+
+	wiki := defaultWiki
+	if startIsURL {
+		if startRef, err = parseArticleURL(start, profiles); err != nil {
+			return
+		}
+		wiki = startRef.WikiBase
+	}
+
+	if targetIsURL {
+		if targetRef, err = parseArticleURL(target, profiles); err != nil {
+			return
+		}
+		if !startIsURL {
+			wiki = targetRef.WikiBase
+		}
+	}
+
+	if !startIsURL {
+		if startRef, err = titleReference(wiki, start, profiles); err != nil {
+			return
+		}
+	}
+
+	if !targetIsURL {
+		if targetRef, err = titleReference(wiki, target, profiles); err != nil {
+			return
+		}
+	}
+
+	// the scheme and the mobile site do not matter, all pages of the start wiki are fetched from the wiki of the start page
+	// and pages of other wikis from their canonical base URL
+	if SameWiki(startRef.WikiBase, targetRef.WikiBase) {
+		targetRef.WikiBase = startRef.WikiBase
+		targetRef.Site = startRef.Site
+	} else {
+		targetRef.WikiBase = canonicalWikiBase(targetRef.WikiBase)
+	}
+	return
+}
+
+func parseArticleURL(rawURL string, profiles SiteProfiles) (ref PageReference, err error) {
+	var parsed *url.URL
+	if parsed, err = url.Parse(rawURL); err != nil {
+		err = fmt.Errorf("%w %q: %v", ErrInvalidArticleURL, rawURL, err)
+		return
+	}
+
+	if !isHTTPURL(parsed) {
+		err = fmt.Errorf("%w %q: expected an http(s) URL", ErrInvalidArticleURL, rawURL)
+		return
+	}
+
+	var site SiteProfile
+	if site, err = profiles.ForURL(rawURL); err != nil {
+		return
+	}
+
+	title, isArticle := site.articleTitle(parsed.RequestURI())
+	if !isArticle {
+		err = fmt.Errorf("%w %q: expected an article URL like %s", ErrInvalidArticleURL, rawURL, site.articleURI(wikiBase(parsed), "Title"))
+		return
+	}
+
+	ref = PageReference{
+		WikiBase: wikiBase(parsed),
+		Title:    title,
+		Site:     site,
+	}
+	return
+}
+
+// titleReference converts a title as displayed by the wiki to the title as used in article paths
+// e.g. "times New Roman" becomes "Times_New_Roman"
+func titleReference(wiki, title string, profiles SiteProfiles) (ref PageReference, err error) {
+	if wiki == "" {
+		err = fmt.Errorf("cannot resolve title %q: %w", title, ErrNoWiki)
+		return
+	}
+
+	var site SiteProfile
+	if site, err = profiles.ForURL(wiki); err != nil {
+		return
+	}
+
+	title = strings.Join(strings.Fields(title), "_")
+	if title == "" {
+		err = ErrEmptyTitle
+		return
+	}
+
+	// MediaWiki titles always start with an upper case letter
+	first, size := utf8.DecodeRuneInString(title)
+	title = string(unicode.ToUpper(first)) + title[size:]
+
+	ref = PageReference{
+		WikiBase: wiki,
+		Title:    (&url.URL{Path: title}).EscapedPath(),
+		Site:     site,
+	}
+	return
+}
+
+func looksLikeURL(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
+
+func isHTTPURL(parsed *url.URL) bool {
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// wikiBase returns the normalized base URL of the wiki the URL belongs to
+func wikiBase(parsed *url.URL) string {
+	return parsed.Scheme + "://" + strings.ToLower(parsed.Host)
+}
+
+// SameWiki checks whether both base URLs refer to the same wiki regardless of the scheme and the mobile site
+func SameWiki(wikiBase, otherWikiBase string) bool {
+	return wikiHost(wikiBase) == wikiHost(otherWikiBase)
+}
+
+// canonicalWikiBase returns the https base URL of the wiki without the mobile subdomain
+// e.g. https://de.wikipedia.org for http://de.m.wikipedia.org
+func canonicalWikiBase(wikiBase string) string {
+	return "https://" + wikiHost(wikiBase)
+}
+
+// wikiHost returns the host of the wiki without the mobile subdomain e.g. de.wikipedia.org for de.m.wikipedia.org
+func wikiHost(wikiBase string) string {
+	host := wikiBase
+	if parsed, err := url.Parse(wikiBase); err == nil {
+		host = strings.ToLower(parsed.Host)
+	}
+
+	if labels := strings.Split(host, "."); len(labels) > 2 && labels[1] == "m" {
+		host = strings.Join(append(labels[:1], labels[2:]...), ".")
+	}
+	return host
+}
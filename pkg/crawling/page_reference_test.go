@@ -0,0 +1,244 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolvePages(t *testing.T) {
+	type args struct {
+		start       string
+		target      string
+		defaultWiki string
+	}
+	tests := []struct {
+		name          string
+		args          args
+		wantStartURI  string
+		wantTargetURI string
+		wantErr       error
+	}{
+		{
+			name: "resolve full URLs",
+			args: args{
+				start:       "https://en.wikipedia.org/wiki/Times_New_Roman",
+				target:      "https://en.wikipedia.org/wiki/Great_Britain",
+				defaultWiki: "https://de.wikipedia.org",
+			},
+			wantStartURI:  "https://en.wikipedia.org/wiki/Times_New_Roman",
+			wantTargetURI: "https://en.wikipedia.org/wiki/Great_Britain",
+		},
+		{
+			name: "resolve titles with default wiki",
+			args: args{
+				start:       "Times New Roman",
+				target:      "großbritannien",
+				defaultWiki: "https://de.wikipedia.org",
+			},
+			wantStartURI:  "https://de.wikipedia.org/wiki/Times_New_Roman",
+			wantTargetURI: "https://de.wikipedia.org/wiki/Gro%C3%9Fbritannien",
+		},
+		{
+			name: "resolve title relative to wiki of other URL",
+			args: args{
+				start:       "Times New Roman",
+				target:      "https://fr.wikipedia.org/wiki/Royaume-Uni",
+				defaultWiki: "https://en.wikipedia.org",
+			},
+			wantStartURI:  "https://fr.wikipedia.org/wiki/Times_New_Roman",
+			wantTargetURI: "https://fr.wikipedia.org/wiki/Royaume-Uni",
+		},
+		{
+			name: "ignore different scheme of target",
+			args: args{
+				start:  "https://en.wikipedia.org/wiki/Times_New_Roman",
+				target: "http://EN.wikipedia.org/wiki/Great_Britain",
+			},
+			wantStartURI:  "https://en.wikipedia.org/wiki/Times_New_Roman",
+			wantTargetURI: "https://en.wikipedia.org/wiki/Great_Britain",
+		},
+		{
+			name: "resolve title relative to mobile Wikipedia",
+			args: args{
+				start:       "https://de.m.wikipedia.org/wiki/Times_New_Roman",
+				target:      "https://de.wikipedia.org/wiki/Gro%C3%9Fbritannien",
+				defaultWiki: "https://en.wikipedia.org",
+			},
+			wantStartURI:  "https://de.m.wikipedia.org/wiki/Times_New_Roman",
+			wantTargetURI: "https://de.m.wikipedia.org/wiki/Gro%C3%9Fbritannien",
+		},
+		{
+			name: "resolve titles in Wikipedia with language variant",
+			args: args{
+				start:       "Times New Roman",
+				target:      "香港",
+				defaultWiki: "https://zh-yue.wikipedia.org",
+			},
+			wantStartURI:  "https://zh-yue.wikipedia.org/wiki/Times_New_Roman",
+			wantTargetURI: "https://zh-yue.wikipedia.org/wiki/%E9%A6%99%E6%B8%AF",
+		},
+		{
+			name: "reject non-article namespace",
+			args: args{
+				start:  "https://en.wikipedia.org/wiki/Category:Typefaces",
+				target: "Great Britain",
+			},
+			wantErr: ErrInvalidArticleURL,
+		},
+		{
+			name: "reject pages of different wikis",
+			args: args{
+				start:  "https://en.wikipedia.org/wiki/Times_New_Roman",
+				target: "https://de.wikipedia.org/wiki/Gro%C3%9Fbritannien",
+			},
+			wantErr: ErrDifferentWikis,
+		},
+		{
+			name: "reject URL without article path",
+			args: args{
+				start:  "https://en.wikipedia.org/w/index.php?title=Times_New_Roman",
+				target: "Great Britain",
+			},
+			wantErr: ErrInvalidArticleURL,
+		},
+		{
+			name: "reject empty title",
+			args: args{
+				start:       "Times New Roman",
+				target:      "  ",
+				defaultWiki: "https://en.wikipedia.org",
+			},
+			wantErr: ErrEmptyTitle,
+		},
+		{
+			name: "reject title without wiki",
+			args: args{
+				start:  "Times New Roman",
+				target: "Great Britain",
+			},
+			wantErr: ErrNoWiki,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStart, gotTarget, err := ResolvePages(tt.args.start, tt.args.target, tt.args.defaultWiki, BuiltinSiteProfiles())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ResolvePages() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if gotStart.URI() != tt.wantStartURI {
+				t.Errorf("ResolvePages() start = %v, want %v", gotStart.URI(), tt.wantStartURI)
+			}
+			if gotTarget.URI() != tt.wantTargetURI {
+				t.Errorf("ResolvePages() target = %v, want %v", gotTarget.URI(), tt.wantTargetURI)
+			}
+		})
+	}
+}
+
+func TestResolvePagesAcrossWikis(t *testing.T) {
+	tests := []struct {
+		name          string
+		start         string
+		target        string
+		wantStartURI  string
+		wantTargetURI string
+	}{
+		{
+			name:          "resolve pages of different language editions",
+			start:         "https://de.wikipedia.org/wiki/Schriftart",
+			target:        "https://en.wikipedia.org/wiki/Times_New_Roman",
+			wantStartURI:  "https://de.wikipedia.org/wiki/Schriftart",
+			wantTargetURI: "https://en.wikipedia.org/wiki/Times_New_Roman",
+		},
+		{
+			name:          "canonicalise target of other wiki given with http scheme",
+			start:         "https://de.wikipedia.org/wiki/Schriftart",
+			target:        "http://en.wikipedia.org/wiki/Times_New_Roman",
+			wantStartURI:  "https://de.wikipedia.org/wiki/Schriftart",
+			wantTargetURI: "https://en.wikipedia.org/wiki/Times_New_Roman",
+		},
+		{
+			name:          "canonicalise target of other wiki given on mobile site",
+			start:         "https://de.wikipedia.org/wiki/Schriftart",
+			target:        "https://en.m.wikipedia.org/wiki/Times_New_Roman",
+			wantStartURI:  "https://de.wikipedia.org/wiki/Schriftart",
+			wantTargetURI: "https://en.wikipedia.org/wiki/Times_New_Roman",
+		},
+		{
+			name:          "resolve target of start wiki relative to start page",
+			start:         "https://de.m.wikipedia.org/wiki/Schriftart",
+			target:        "http://de.wikipedia.org/wiki/Serife",
+			wantStartURI:  "https://de.m.wikipedia.org/wiki/Schriftart",
+			wantTargetURI: "https://de.m.wikipedia.org/wiki/Serife",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, target, err := ResolvePagesAcrossWikis(tt.start, tt.target, "", BuiltinSiteProfiles())
+			if err != nil {
+				t.Fatalf("ResolvePagesAcrossWikis() error = %v", err)
+			}
+			if start.URI() != tt.wantStartURI {
+				t.Errorf("ResolvePagesAcrossWikis() start = %v, want %v", start.URI(), tt.wantStartURI)
+			}
+			if target.URI() != tt.wantTargetURI {
+				t.Errorf("ResolvePagesAcrossWikis() target = %v, want %v", target.URI(), tt.wantTargetURI)
+			}
+		})
+	}
+}
+
+func TestWikipediaBaseURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "simple language code",
+			language: "de",
+			want:     "https://de.wikipedia.org",
+		},
+		{
+			name:     "language code with variant",
+			language: "zh-yue",
+			want:     "https://zh-yue.wikipedia.org",
+		},
+		{
+			name:     "invalid language code",
+			language: "en.evil.com/",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WikipediaBaseURL(tt.language)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WikipediaBaseURL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("WikipediaBaseURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
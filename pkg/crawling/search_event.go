@@ -0,0 +1,81 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"time"
+)
+
+const (
+	// LevelStarted is the kind of events passed to Observer.OnLevelStart
+	LevelStarted SearchEventKind = "level_started"
+	// LevelFinished is the kind of events passed to Observer.OnLevelFinished
+	LevelFinished SearchEventKind = "level_finished"
+	// PageProcessed is the kind of events passed to Observer.OnPageProcessed
+	PageProcessed SearchEventKind = "page_processed"
+	// PathFound is the kind of events passed to Observer.OnPathFound
+	PathFound SearchEventKind = "path_found"
+)
+
+// SearchEventKind names the step of the search an event is emitted for
+type SearchEventKind string
+
+// SearchEvent reports the progress of a running search at one of its steps to an Observer
+type SearchEvent struct {
+	Kind     SearchEventKind
+	Progress SearchProgress
+	// Elapsed is the duration since the search was started or resumed
+	Elapsed time.Duration
+	// PagesPerSecond is the rate of fetched pages since the search was started or resumed
+	PagesPerSecond float64
+	// Path is the resolved path of PathFound events
+	Path []PathPage
+}
+
+// searchClock measures the duration and the fetch rate of a search since it was started or resumed
+type searchClock struct {
+	started      time.Time
+	fetchedPages uint
+}
+
+// notifySearchEvent passes the event of the given kind to the observers, the event is only built if any observer is registered
+func (crawler *WikiCrawler) notifySearchEvent(kind SearchEventKind, clock searchClock, path []PathPage) {
+	if len(crawler.observers) == 0 {
+		return
+	}
+
+	event := SearchEvent{
+		Kind:     kind,
+		Progress: crawler.Progress(),
+		Elapsed:  time.Since(clock.started),
+		Path:     path,
+	}
+	if seconds := event.Elapsed.Seconds(); seconds > 0 {
+		event.PagesPerSecond = float64(event.Progress.FetchedPages-clock.fetchedPages) / seconds
+	}
+
+	for _, observer := range crawler.observers {
+		switch kind {
+		case LevelStarted:
+			observer.OnLevelStart(event)
+		case LevelFinished:
+			observer.OnLevelFinished(event)
+		case PageProcessed:
+			observer.OnPageProcessed(event)
+		case PathFound:
+			observer.OnPathFound(event)
+		}
+	}
+}
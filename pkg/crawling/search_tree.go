@@ -0,0 +1,123 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import "sort"
+
+// SearchTreeNode is a discovered page in the tree spanned by the BFS.
+// Every page is attached to the page it was discovered on first.
+type SearchTreeNode struct {
+	ID        uint32
+	ParentID  uint32
+	HasParent bool
+	Title     string
+	URI       string
+	Depth     int
+	// Descendants is the number of pages discovered below this page
+	Descendants int
+	OnPath      bool
+	// Interlanguage is set if the page was discovered by an interlanguage link of its parent
+	Interlanguage bool
+}
+
+// SearchTree returns the explored search tree in BFS order i.e. parents always precede their children.
+// If maxBranchesPerLevel is greater than 0 only the pages with the most descendants are kept on every level,
+// pages on the resolved path are always kept.
+func (crawler WikiCrawler) SearchTree(result TraversalResult, maxBranchesPerLevel int) (nodes []SearchTreeNode) {
+	count := crawler.titles.Len()
+	depths := make([]int, count)
+	descendants := make([]int, count)
+	onPath := make([]bool, count)
+
+	for _, id := range result.path {
+		onPath[id] = true
+	}
+
+	// page IDs are assigned in discovery order, hence parents always have lower IDs than their children
+	for id := 0; id < count; id++ {
+		if parent, ok := crawler.parents.Parent(pageID(id)); ok {
+			depths[id] = depths[parent] + 1
+		}
+	}
+
+	for id := count - 1; id >= 0; id-- {
+		if parent, ok := crawler.parents.Parent(pageID(id)); ok {
+			descendants[parent] += descendants[id] + 1
+		}
+	}
+
+	levels := make([][]pageID, 0)
+	for id := 0; id < count; id++ {
+		for len(levels) <= depths[id] {
+			levels = append(levels, nil)
+		}
+		levels[depths[id]] = append(levels[depths[id]], pageID(id))
+	}
+
+	// the candidates of every level depend on the pruning of the previous level
+	kept := make([]bool, count)
+	for _, level := range levels {
+		candidates := make([]pageID, 0, len(level))
+		for _, id := range level {
+			if parent, hasParent := crawler.parents.Parent(id); !hasParent || kept[parent] {
+				candidates = append(candidates, id)
+			}
+		}
+		for _, id := range pruneLevel(candidates, maxBranchesPerLevel, descendants, onPath) {
+			kept[id] = true
+		}
+	}
+
+	for id := 0; id < count; id++ {
+		if !kept[id] {
+			continue
+		}
+		parent, hasParent := crawler.parents.Parent(pageID(id))
+		title := crawler.titles.Title(pageID(id))
+		nodes = append(nodes, SearchTreeNode{
+			ID:            uint32(id),
+			ParentID:      uint32(parent),
+			HasParent:     hasParent,
+			Title:         displayTitle(title),
+			URI:           crawler.titleURI(title),
+			Depth:         depths[id],
+			Descendants:   descendants[id],
+			OnPath:        onPath[id],
+			Interlanguage: crawler.interlanguageEdges[pageID(id)],
+		})
+	}
+	return
+}
+
+// pruneLevel keeps the maxBranches pages with the most descendants as well as all pages on the path
+func pruneLevel(level []pageID, maxBranches int, descendants []int, onPath []bool) []pageID {
+	if maxBranches <= 0 || len(level) <= maxBranches {
+		return level
+	}
+
+	ranked := make([]pageID, len(level))
+	copy(ranked, level)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return descendants[ranked[i]] > descendants[ranked[j]]
+	})
+
+	kept := ranked[:maxBranches]
+	for _, id := range ranked[maxBranches:] {
+		if onPath[id] {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
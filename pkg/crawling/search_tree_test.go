@@ -0,0 +1,101 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"context"
+	"github.com/baez90/shortest-path/pkg/crawling/crawlingtest"
+	"reflect"
+	"testing"
+)
+
+func TestWikiCrawler_SearchTree(t *testing.T) {
+	type args struct {
+		graph               crawlingtest.Tree
+		target              int
+		maxBranchesPerLevel int
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantNodes   []uint32
+		wantOnPath  []uint32
+		wantParents map[uint32]uint32
+	}{
+		{
+			name: "Complete tree",
+			args: args{
+				graph:  crawlingtest.Tree{Branching: 2, Nodes: 20},
+				target: 8,
+			},
+			wantNodes:   []uint32{0, 1, 2, 3, 4, 5, 6, 7, 8},
+			wantOnPath:  []uint32{0, 1, 3, 8},
+			wantParents: map[uint32]uint32{1: 0, 2: 0, 3: 1, 4: 1, 5: 2, 6: 2, 7: 3, 8: 3},
+		},
+		{
+			name: "Tree pruned to a single branch per level",
+			args: args{
+				graph:               crawlingtest.Tree{Branching: 2, Nodes: 20},
+				target:              8,
+				maxBranchesPerLevel: 1,
+			},
+			wantNodes:   []uint32{0, 1, 3, 7, 8},
+			wantOnPath:  []uint32{0, 1, 3, 8},
+			wantParents: map[uint32]uint32{1: 0, 3: 1, 7: 3, 8: 3},
+		},
+		{
+			name: "Tree pruned keeps path and biggest branches",
+			args: args{
+				graph:               crawlingtest.Tree{Branching: 3, Nodes: 40},
+				target:              16,
+				maxBranchesPerLevel: 2,
+			},
+			wantNodes:   []uint32{0, 1, 2, 4, 5, 13, 14, 16},
+			wantOnPath:  []uint32{0, 1, 5, 16},
+			wantParents: map[uint32]uint32{1: 0, 2: 0, 4: 1, 5: 1, 13: 4, 14: 4, 16: 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crawler := treeCrawler(tt.args.graph, tt.args.target, 10)
+			result, err := crawler.SearchShortestPath(context.Background())
+			if err != nil {
+				t.Fatalf("SearchShortestPath() error = %v", err)
+			}
+
+			var gotNodes, gotOnPath []uint32
+			gotParents := make(map[uint32]uint32)
+			for _, node := range crawler.SearchTree(result, tt.args.maxBranchesPerLevel) {
+				gotNodes = append(gotNodes, node.ID)
+				if node.OnPath {
+					gotOnPath = append(gotOnPath, node.ID)
+				}
+				if node.HasParent {
+					gotParents[node.ID] = node.ParentID
+				}
+			}
+
+			if !reflect.DeepEqual(gotNodes, tt.wantNodes) {
+				t.Errorf("SearchTree() nodes = %v, want %v", gotNodes, tt.wantNodes)
+			}
+			if !reflect.DeepEqual(gotOnPath, tt.wantOnPath) {
+				t.Errorf("SearchTree() nodes on path = %v, want %v", gotOnPath, tt.wantOnPath)
+			}
+			if !reflect.DeepEqual(gotParents, tt.wantParents) {
+				t.Errorf("SearchTree() parents = %v, want %v", gotParents, tt.wantParents)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	articlePathPlaceholder = "$1"
+)
+
+var (
+	mediaWikiNamespaces = []string{
+		"Media", "Special", "Talk", "User", "Project", "File", "Image", "MediaWiki",
+		"Template", "Help", "Category", "Module",
+	}
+	wikimediaNamespaces = append([]string{
+		"Wikipedia", "WP", "Portal", "Draft", "TimedText", "Book", "Gadget", "Gadget_definition", "Education_Program",
+	}, mediaWikiNamespaces...)
+	wiktionaryNamespaces = append([]string{
+		"Wiktionary", "WT", "Appendix", "Citations", "Concordance", "Index", "Rhymes", "Thesaurus", "Reconstruction",
+	}, mediaWikiNamespaces...)
+	wikivoyageNamespaces = append([]string{
+		"Wikivoyage", "WV",
+	}, mediaWikiNamespaces...)
+	fandomNamespaces = append([]string{
+		"Blog", "User_blog", "User_blog_comment", "Message_Wall", "Message_Wall_Greeting", "Board", "Thread", "Forum",
+	}, mediaWikiNamespaces...)
+
+	bodyContentSelector = ElementSelector{Element: "div", Attribute: "id", Value: "bodyContent"}
+
+	builtinSiteProfiles = mustCompile(SiteProfiles{
+		{
+			Name:        "wikipedia",
+			HostPattern: `^[a-z0-9-]+(\.m)?\.wikipedia\.org$`,
+			ArticlePath: "/wiki/$1",
+			ContentRoot: bodyContentSelector,
+			Namespaces:  wikimediaNamespaces,
+		},
+		{
+			Name:        "wiktionary",
+			HostPattern: `^[a-z0-9-]+(\.m)?\.wiktionary\.org$`,
+			ArticlePath: "/wiki/$1",
+			ContentRoot: bodyContentSelector,
+			Namespaces:  wiktionaryNamespaces,
+		},
+		{
+			Name:        "wikivoyage",
+			HostPattern: `^[a-z0-9-]+(\.m)?\.wikivoyage\.org$`,
+			ArticlePath: "/wiki/$1",
+			ContentRoot: bodyContentSelector,
+			Namespaces:  wikivoyageNamespaces,
+		},
+		{
+			Name:        "fandom",
+			HostPattern: `^[a-z0-9-]+\.fandom\.com$`,
+			ArticlePath: "/wiki/$1",
+			ContentRoot: ElementSelector{Element: "div", Attribute: "id", Value: "mw-content-text"},
+			Namespaces:  fandomNamespaces,
+		},
+		{
+			Name:        "mediawiki",
+			HostPattern: `.*`,
+			ArticlePath: "/wiki/$1",
+			ContentRoot: bodyContentSelector,
+			Namespaces:  mediaWikiNamespaces,
+		},
+	})
+)
+
+// ElementSelector selects the first element of the given type with the given attribute value.
+// Class attributes match if the element has the class among others.
+type ElementSelector struct {
+	Element   string `yaml:"element"`
+	Attribute string `yaml:"attribute"`
+	Value     string `yaml:"value"`
+}
+
+// SiteProfile describes the URL layout and the page structure of a MediaWiki based site
+type SiteProfile struct {
+	Name string `yaml:"name"`
+	// BaseURL restricts the profile to a single wiki e.g. https://wiki.example.com, takes precedence over HostPattern
+	BaseURL string `yaml:"base_url"`
+	// HostPattern is a regular expression matching the hosts of all wikis the profile applies to
+	HostPattern string `yaml:"host_pattern"`
+	// ArticlePath is the path of articles with $1 as placeholder for the title like MediaWiki's $wgArticlePath
+	ArticlePath string `yaml:"article_path"`
+	// ContentRoot selects the element containing the article content, links outside of it are ignored
+	ContentRoot ElementSelector `yaml:"content_root"`
+	// Namespaces are title prefixes of pages which are no articles e.g. File or Category, talk namespaces are implied
+	Namespaces []string `yaml:"namespaces"`
+	// hostRegexp is the compiled HostPattern, nil for profiles which were not loaded or validated by this package
+	hostRegexp *regexp.Regexp
+}
+
+// SiteProfiles is an ordered list of profiles, the first matching profile applies
+type SiteProfiles []SiteProfile
+
+// BuiltinSiteProfiles returns the profiles for Wikipedia, Wiktionary, Wikivoyage, Fandom and a fallback for any MediaWiki
+func BuiltinSiteProfiles() SiteProfiles {
+	profiles := make(SiteProfiles, len(builtinSiteProfiles))
+	copy(profiles, builtinSiteProfiles)
+	return profiles
+}
+
+// LoadSiteProfiles reads custom profiles from a YAML file with a top level sites list.
+// The custom profiles take precedence over the built-in ones.
+func LoadSiteProfiles(path string) (profiles SiteProfiles, err error) {
+	var content []byte
+	if content, err = ioutil.ReadFile(path); err != nil {
+		return
+	}
+
+	var file struct {
+		Sites SiteProfiles `yaml:"sites"`
+	}
+	if err = yaml.UnmarshalStrict(content, &file); err != nil {
+		err = fmt.Errorf("failed to parse site profiles %s: %w", path, err)
+		return
+	}
+
+	for idx := range file.Sites {
+		if err = file.Sites[idx].compile(); err != nil {
+			err = fmt.Errorf("invalid site profile in %s: %w", path, err)
+			return
+		}
+	}
+
+	profiles = append(file.Sites, BuiltinSiteProfiles()...)
+	return
+}
+
+// Validate checks that the profile can be matched against URLs and is able to build article paths
+func (profile SiteProfile) Validate() error {
+	return profile.compile()
+}
+
+// compile validates the profile and keeps the compiled host pattern to match URLs against
+func (profile *SiteProfile) compile() (err error) {
+	if profile.Name == "" {
+		return fmt.Errorf("site profile without name")
+	}
+
+	switch {
+	case profile.BaseURL != "":
+		if _, err = ParseWikiBaseURL(profile.BaseURL); err != nil {
+			return fmt.Errorf("site profile %s: %w", profile.Name, err)
+		}
+	case profile.HostPattern != "":
+		if profile.hostRegexp, err = regexp.Compile(profile.HostPattern); err != nil {
+			return fmt.Errorf("site profile %s: invalid host pattern: %w", profile.Name, err)
+		}
+	default:
+		return fmt.Errorf("site profile %s requires either a base URL or a host pattern", profile.Name)
+	}
+
+	if !strings.HasPrefix(profile.ArticlePath, "/") || strings.Count(profile.ArticlePath, articlePathPlaceholder) != 1 {
+		return fmt.Errorf("site profile %s: article path %q has to be absolute and contain %s exactly once", profile.Name, profile.ArticlePath, articlePathPlaceholder)
+	}
+
+	if profile.ContentRoot.Element == "" || profile.ContentRoot.Attribute == "" || profile.ContentRoot.Value == "" {
+		return fmt.Errorf("site profile %s: content root requires element, attribute and value", profile.Name)
+	}
+	return
+}
+
+// ForURL returns the first profile applying to the wiki of the given URL
+func (profiles SiteProfiles) ForURL(rawURL string) (profile SiteProfile, err error) {
+	var parsed *url.URL
+	if parsed, err = url.Parse(rawURL); err != nil {
+		err = fmt.Errorf("%w %q: %v", ErrInvalidWikiURL, rawURL, err)
+		return
+	}
+
+	for _, profile = range profiles {
+		if profile.matches(parsed) {
+			return
+		}
+	}
+
+	err = fmt.Errorf("%w for %s", ErrNoSiteProfile, rawURL)
+	return
+}
+
+func (profile SiteProfile) matches(parsed *url.URL) bool {
+	if profile.BaseURL != "" {
+		return SameWiki(profile.BaseURL, wikiBase(parsed))
+	}
+	hostRegexp := profile.hostRegexp
+	if hostRegexp == nil {
+		// profiles assembled by hand are not validated yet
+		var err error
+		if hostRegexp, err = regexp.Compile(profile.HostPattern); err != nil {
+			return false
+		}
+	}
+	return hostRegexp.MatchString(strings.ToLower(parsed.Hostname()))
+}
+
+// mustCompile compiles the host patterns of the built-in profiles, which are known to be valid
+func mustCompile(profiles SiteProfiles) SiteProfiles {
+	for idx := range profiles {
+		if err := profiles[idx].compile(); err != nil {
+			panic(err)
+		}
+	}
+	return profiles
+}
+
+// articleURI builds the URI of the article with the given escaped title
+func (profile SiteProfile) articleURI(wikiBase, title string) string {
+	return wikiBase + strings.Replace(profile.ArticlePath, articlePathPlaceholder, title, 1)
+}
+
+// articleTitle extracts the escaped title from a relative link e.g. /wiki/Times_New_Roman#History.
+// Links to other pages than articles e.g. files, categories or edit links are rejected.
+func (profile SiteProfile) articleTitle(href string) (title string, ok bool) {
+	title, _, reason := profile.classifyHref(href)
+	return title, reason == ""
+}
+
+// classifyHref extracts the escaped title from a relative link and explains why the link is no article link.
+// The reason is empty for article links, the namespace is set if the title belongs to a non-article namespace.
+func (profile SiteProfile) classifyHref(href string) (title, namespace, reason string) {
+	placeholder := strings.Index(profile.ArticlePath, articlePathPlaceholder)
+	if placeholder < 0 {
+		return "", "", "site profile has no valid article path"
+	}
+	prefix, suffix := profile.ArticlePath[:placeholder], profile.ArticlePath[placeholder+len(articlePathPlaceholder):]
+
+	if strings.HasPrefix(href, "#") {
+		return "", "", "fragment of the same page"
+	}
+
+	if fragment := strings.IndexByte(href, '#'); fragment >= 0 {
+		href = href[:fragment]
+	}
+
+	if !strings.HasPrefix(href, prefix) || !strings.HasSuffix(href, suffix) || len(href) <= len(prefix)+len(suffix) {
+		return "", "", "no article path"
+	}
+	title = href[len(prefix) : len(href)-len(suffix)]
+
+	// any further query parameter selects an action or a revision instead of the article
+	separator := "?"
+	if strings.Contains(prefix, "?") {
+		separator = "&"
+	}
+	if strings.Contains(title, separator) {
+		return "", "", "links to an action or revision instead of the article"
+	}
+
+	if namespace = profile.namespace(title); namespace != "" {
+		return "", namespace, "non-article namespace " + namespace
+	}
+	return title, "", ""
+}
+
+// namespace returns the non-article namespace the title belongs to including talk namespaces, empty for articles
+func (profile SiteProfile) namespace(title string) string {
+	colon := strings.IndexByte(title, ':')
+	if colon < 0 {
+		return ""
+	}
+
+	namespace := title[:colon]
+	if unescaped, err := url.PathUnescape(namespace); err == nil {
+		namespace = unescaped
+	}
+	namespace = strings.Replace(namespace, " ", "_", -1)
+	subject := strings.TrimSuffix(strings.ToLower(namespace), "_talk")
+
+	for _, candidate := range profile.Namespaces {
+		if strings.ToLower(candidate) == subject {
+			return namespace
+		}
+	}
+	return ""
+}
@@ -0,0 +1,261 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSiteProfiles_ForURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		rawURL      string
+		wantProfile string
+	}{
+		{
+			name:        "desktop Wikipedia",
+			rawURL:      "https://en.wikipedia.org/wiki/Times_New_Roman",
+			wantProfile: "wikipedia",
+		},
+		{
+			name:        "mobile Wikipedia",
+			rawURL:      "https://de.m.wikipedia.org/wiki/Times_New_Roman",
+			wantProfile: "wikipedia",
+		},
+		{
+			name:        "Wikipedia with language variant",
+			rawURL:      "https://zh-yue.wikipedia.org",
+			wantProfile: "wikipedia",
+		},
+		{
+			name:        "Wiktionary",
+			rawURL:      "https://en.wiktionary.org/wiki/path",
+			wantProfile: "wiktionary",
+		},
+		{
+			name:        "Wikivoyage",
+			rawURL:      "https://en.m.wikivoyage.org/wiki/Berlin",
+			wantProfile: "wikivoyage",
+		},
+		{
+			name:        "Fandom",
+			rawURL:      "https://starwars.fandom.com/wiki/Luke_Skywalker",
+			wantProfile: "fandom",
+		},
+		{
+			name:        "self hosted MediaWiki",
+			rawURL:      "https://wiki.example.com/wiki/Main_Page",
+			wantProfile: "mediawiki",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := BuiltinSiteProfiles().ForURL(tt.rawURL)
+			if err != nil {
+				t.Fatalf("ForURL() error = %v", err)
+			}
+			if profile.Name != tt.wantProfile {
+				t.Errorf("ForURL() = %s, want %s", profile.Name, tt.wantProfile)
+			}
+		})
+	}
+}
+
+func TestSiteProfile_articleTitle(t *testing.T) {
+	wikipedia := builtinSiteProfiles[0]
+	indexPHP := SiteProfile{Name: "index", ArticlePath: "/w/index.php?title=$1", Namespaces: mediaWikiNamespaces}
+	tests := []struct {
+		name      string
+		profile   SiteProfile
+		href      string
+		wantTitle string
+		wantOk    bool
+	}{
+		{
+			name:      "plain article",
+			profile:   wikipedia,
+			href:      "/wiki/Times_New_Roman",
+			wantTitle: "Times_New_Roman",
+			wantOk:    true,
+		},
+		{
+			name:      "strip fragment",
+			profile:   wikipedia,
+			href:      "/wiki/Typeface#Serif",
+			wantTitle: "Typeface",
+			wantOk:    true,
+		},
+		{
+			name:      "escaped title with digits",
+			profile:   wikipedia,
+			href:      "/wiki/Joan_Micha%C3%ABl_Fleischman",
+			wantTitle: "Joan_Micha%C3%ABl_Fleischman",
+			wantOk:    true,
+		},
+		{
+			name:      "colon without namespace",
+			profile:   wikipedia,
+			href:      "/wiki/Star_Wars:_Episode_I_%E2%80%93_The_Phantom_Menace",
+			wantTitle: "Star_Wars:_Episode_I_%E2%80%93_The_Phantom_Menace",
+			wantOk:    true,
+		},
+		{
+			name:    "fragment of the same page",
+			profile: wikipedia,
+			href:    "#cite_note-1",
+		},
+		{
+			name:    "file namespace",
+			profile: wikipedia,
+			href:    "/wiki/File:Times_New_Roman-sample.svg",
+		},
+		{
+			name:    "talk namespace",
+			profile: wikipedia,
+			href:    "/wiki/Template_talk:Typography",
+		},
+		{
+			name:    "edit link",
+			profile: wikipedia,
+			href:    "/w/index.php?title=Times_New_Roman&action=edit",
+		},
+		{
+			name:    "article path only",
+			profile: wikipedia,
+			href:    "/wiki/",
+		},
+		{
+			name:      "title as query parameter",
+			profile:   indexPHP,
+			href:      "/w/index.php?title=Main_Page",
+			wantTitle: "Main_Page",
+			wantOk:    true,
+		},
+		{
+			name:    "action as additional query parameter",
+			profile: indexPHP,
+			href:    "/w/index.php?title=Main_Page&action=history",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTitle, gotOk := tt.profile.articleTitle(tt.href)
+			if gotTitle != tt.wantTitle || gotOk != tt.wantOk {
+				t.Errorf("articleTitle() = (%s, %v), want (%s, %v)", gotTitle, gotOk, tt.wantTitle, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestLoadSiteProfiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		rawURL      string
+		wantProfile string
+		wantErr     bool
+	}{
+		{
+			name: "custom profile takes precedence",
+			content: `sites:
+  - name: example
+    base_url: https://wiki.example.com
+    article_path: /index.php/$1
+    content_root:
+      element: div
+      attribute: class
+      value: mw-parser-output
+    namespaces: [Special, File]
+`,
+			rawURL:      "https://wiki.example.com/index.php/Main_Page",
+			wantProfile: "example",
+		},
+		{
+			name: "custom host pattern",
+			content: `sites:
+  - name: example
+    host_pattern: ^[a-z]+\.example\.com$
+    article_path: /wiki/$1
+    content_root: {element: div, attribute: id, value: bodyContent}
+`,
+			rawURL:      "https://de.example.com/wiki/Hauptseite",
+			wantProfile: "example",
+		},
+		{
+			name:        "built-in profiles remain available",
+			content:     "sites: []\n",
+			rawURL:      "https://en.wikipedia.org/wiki/Times_New_Roman",
+			wantProfile: "wikipedia",
+		},
+		{
+			name: "reject article path without placeholder",
+			content: `sites:
+  - name: example
+    host_pattern: ^wiki\.example\.com$
+    article_path: /wiki/
+    content_root: {element: div, attribute: id, value: bodyContent}
+`,
+			wantErr: true,
+		},
+		{
+			name: "reject unknown keys",
+			content: `sites:
+  - name: example
+    hosts: wiki.example.com
+`,
+			wantErr: true,
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "site-profiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for idx, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.Repeat("x", idx+1)+".yaml")
+			if err := ioutil.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			profiles, err := LoadSiteProfiles(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadSiteProfiles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			for _, profile := range profiles {
+				if profile.BaseURL == "" && profile.hostRegexp == nil {
+					t.Errorf("host pattern of profile %s is not compiled", profile.Name)
+				}
+			}
+
+			profile, err := profiles.ForURL(tt.rawURL)
+			if err != nil {
+				t.Fatalf("ForURL() error = %v", err)
+			}
+			if profile.Name != tt.wantProfile {
+				t.Errorf("ForURL() = %s, want %s", profile.Name, tt.wantProfile)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"context"
+)
+
+// Tracer records the steps of a search as nested spans e.g. to export them via OpenTelemetry.
+// A tracer shared by multiple crawlers has to be safe for concurrent use.
+type Tracer interface {
+	// Start begins a span as child of the span carried by ctx, the returned context carries the new span
+	Start(ctx context.Context, name string, attributes ...SpanAttribute) (context.Context, Span)
+}
+
+// Span is a single traced step of a search
+type Span interface {
+	// SetAttributes adds attributes describing the outcome of the step
+	SetAttributes(attributes ...SpanAttribute)
+	// End finishes the span, a non-nil err marks the step as failed
+	End(err error)
+}
+
+// SpanAttribute describes a span, values are strings, bools or integers
+type SpanAttribute struct {
+	Key   string
+	Value interface{}
+}
+
+func attribute(key string, value interface{}) SpanAttribute {
+	return SpanAttribute{Key: key, Value: value}
+}
+
+// nopTracer records nothing, it is used unless WithTracer is given
+type nopTracer struct{}
+
+func (nopTracer) Start(ctx context.Context, _ string, _ ...SpanAttribute) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttributes(...SpanAttribute) {}
+func (nopSpan) End(error)                      {}
@@ -0,0 +1,567 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"context"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// pageFetcher retrieves the raw HTML of the page with the given URI
+type pageFetcher func(ctx context.Context, pageURI string) (io.ReadCloser, error)
+
+// NewWikiCrawler creates a crawler searching from startPage to targetPage, both have to be full article URLs
+// of a wiki covered by the built-in site profiles, otherwise ErrInvalidArticleURL is returned.
+// Use ResolvePages and NewWikiCrawlerForPages to resolve titles and to use custom site profiles.
+func NewWikiCrawler(startPage string, targetPage string, options ...Option) (crawler *WikiCrawler, err error) {
+	var startRef, targetRef PageReference
+	if startRef, err = parseArticleURL(startPage, BuiltinSiteProfiles()); err != nil {
+		return
+	}
+	if targetRef, err = parseArticleURL(targetPage, BuiltinSiteProfiles()); err != nil {
+		return
+	}
+	crawler = NewWikiCrawlerForPages(startRef, targetRef, options...)
+	return
+}
+
+// NewWikiCrawlerForPages creates a crawler for pages resolved by ResolvePages.
+// All pages are parsed according to the site profile of the start page.
+// A target in another language edition is only reachable with WithInterlanguageLinks.
+func NewWikiCrawlerForPages(start, target PageReference, options ...Option) *WikiCrawler {
+	crawler := &WikiCrawler{
+		titles:         newTitleInterner(),
+		parents:        &parentMap{},
+		fetchPage:      FetchPage,
+		startTitle:     start.Title,
+		targetTitle:    qualifiedTitle(start.WikiBase, target.WikiBase, target.Title),
+		wikiBaseDomain: start.WikiBase,
+		site:           start.Site,
+		maxHops:        DefaultMaxHops,
+		progress:       &progressTracker{},
+		tracer:         nopTracer{},
+	}
+	for _, option := range options {
+		option(crawler)
+	}
+	return crawler
+}
+
+// WikiCrawler searches the shortest path between two pages of a wiki by a breadth first search over their links.
+// A crawler runs one search at a time, all methods except Progress must not be called while it is running.
+type WikiCrawler struct {
+	titles              *titleInterner
+	parents             *parentMap
+	fetchPage           pageFetcher
+	startTitle          string
+	targetTitle         string
+	wikiBaseDomain      string
+	site                SiteProfile
+	maxHops             uint16
+	fetchedPages        uint
+	spillDirectory      string
+	frontierMemoryLimit uint64
+	checkpointPath      string
+	checkpointInterval  time.Duration
+	lastCheckpoint      time.Time
+	resumeState         *searchState
+	progress            *progressTracker
+	linkContexts        map[pageID]LinkContext
+	// interlanguageEdges marks the pages discovered by an interlanguage link, nil if interlanguage links are disabled
+	interlanguageEdges map[pageID]bool
+	observers          []Observer
+	tracer             Tracer
+	// linkCache shares the links of processed pages with other crawlers, nil if links are not shared
+	linkCache *LinkCache
+	// cachedPages counts the pages whose links were answered by the link cache without retrieving them
+	cachedPages uint
+}
+
+// searchState is the progress of a running search
+type searchState struct {
+	depth   uint16
+	current frontier
+	next    frontier
+	// processed counts the pages of the current level which are already processed
+	processed uint64
+}
+
+// advance continues with the next level after the current level is completely processed
+func (state *searchState) advance(next frontier) {
+	if err := state.current.Close(); err != nil {
+		log.WithError(err).Warn("Failed to release frontier")
+	}
+	state.current = state.next
+	state.next = next
+	state.processed = 0
+	state.depth += 1
+}
+
+func (state *searchState) Close() {
+	for _, f := range []frontier{state.current, state.next} {
+		if err := f.Close(); err != nil {
+			log.WithError(err).Warn("Failed to release frontier")
+		}
+	}
+}
+
+func (crawler WikiCrawler) FetchedPages() uint {
+	return crawler.fetchedPages
+}
+
+// CachedPages is the number of pages whose links were taken from the link cache, they are not counted as fetched pages
+func (crawler WikiCrawler) CachedPages() uint {
+	return crawler.cachedPages
+}
+
+func (crawler WikiCrawler) DiscoveredPages() int {
+	return crawler.titles.Len()
+}
+
+// Progress returns a snapshot of the current or - if no search is running - the latest search.
+// It is safe to call while the search is running in another goroutine.
+func (crawler WikiCrawler) Progress() SearchProgress {
+	return crawler.progress.get()
+}
+
+func (crawler WikiCrawler) StartPage() string {
+	return crawler.titleURI(crawler.startTitle)
+}
+
+func (crawler WikiCrawler) TargetPage() string {
+	return crawler.titleURI(crawler.targetTitle)
+}
+
+// SearchShortestPath runs a breadth first search from the start page to the target page.
+// Only the frontier of the current and the next level is kept, the path is reconstructed from the parent map.
+// If checkpoints are enabled the progress is periodically persisted so that the search can be resumed later on.
+// When ctx is cancelled the search stops after the page currently being processed and returns the context's error,
+// if checkpoints are enabled a final checkpoint is written before.
+func (crawler *WikiCrawler) SearchShortestPath(ctx context.Context) (traversalResult TraversalResult, err error) {
+	ctx, span := crawler.tracer.Start(ctx, "SearchShortestPath",
+		attribute("search.start", crawler.StartPage()),
+		attribute("search.target", crawler.TargetPage()),
+		attribute("search.max_hops", uint32(crawler.maxHops)),
+	)
+	defer func() {
+		span.SetAttributes(
+			attribute("search.found", traversalResult.Found()),
+			attribute("search.hops", traversalResult.Hops()),
+			attribute("search.fetched_pages", crawler.fetchedPages),
+			attribute("search.cached_pages", crawler.cachedPages),
+			attribute("search.discovered_pages", crawler.titles.Len()),
+		)
+		// not reaching the target within max hops is a regular outcome of the search
+		if err == ErrMaxHopsReached {
+			span.End(nil)
+		} else {
+			span.End(err)
+		}
+	}()
+
+	var state *searchState
+	if state, err = crawler.initSearchState(); err != nil {
+		return
+	}
+	defer state.Close()
+
+	clock := searchClock{started: time.Now(), fetchedPages: crawler.fetchedPages}
+	defer func() {
+		crawler.notifySearchFinished(traversalResult.Hops(), time.Since(clock.started), err)
+	}()
+
+	// the target is only compared with discovered links, the start page is never discovered as it is known up front
+	if crawler.startTitle == crawler.targetTitle {
+		startID, _ := crawler.titles.Intern(crawler.startTitle)
+		traversalResult = crawler.pathResult(startID)
+		crawler.updateProgress(state)
+		crawler.notifySearchEvent(PathFound, clock, traversalResult.Path())
+		return
+	}
+
+	for {
+		crawler.updateProgress(state)
+
+		if state.depth >= crawler.maxHops {
+			err = ErrMaxHopsReached
+			return
+		}
+		crawler.notifySearchEvent(LevelStarted, clock, nil)
+
+		levelCtx, levelSpan := crawler.tracer.Start(ctx, "level",
+			attribute("level.depth", uint32(state.depth)),
+			attribute("level.frontier_size", state.current.Len()),
+		)
+
+		var position uint64
+		err = state.current.Iterate(func(id pageID) (iterErr error) {
+			// skip pages already processed before the search was resumed
+			if position += 1; position <= state.processed {
+				return
+			}
+
+			if iterErr = ctx.Err(); iterErr != nil {
+				return
+			}
+
+			discoveredBefore := crawler.titles.Len()
+			var discoveredLinks []pageID
+			if traversalResult, discoveredLinks = crawler.processState(levelCtx, id); traversalResult.Found() {
+				crawler.updateProgress(state)
+				crawler.notifySearchEvent(PathFound, clock, traversalResult.Path())
+				return errPathFound
+			}
+
+			// the page might be processed only partially if the search was cancelled meanwhile,
+			// forget about its links to process it again completely when the search is resumed
+			if iterErr = ctx.Err(); iterErr != nil {
+				crawler.forgetPagesFrom(discoveredBefore)
+				return
+			}
+
+			for _, link := range discoveredLinks {
+				if iterErr = state.next.Push(link); iterErr != nil {
+					return
+				}
+			}
+
+			state.processed += 1
+			crawler.updateProgress(state)
+			crawler.notifySearchEvent(PageProcessed, clock, nil)
+			crawler.checkpointIfDue(state)
+			return
+		})
+
+		levelSpan.SetAttributes(
+			attribute("level.processed_pages", state.processed),
+			attribute("level.next_frontier_size", state.next.Len()),
+		)
+		if err == errPathFound {
+			levelSpan.End(nil)
+		} else {
+			levelSpan.End(err)
+		}
+
+		if err != nil {
+			crawler.updateProgress(state)
+			switch {
+			case err == errPathFound:
+				err = nil
+			case ctx.Err() != nil && crawler.checkpointPath != "":
+				if checkpointErr := crawler.writeCheckpoint(state); checkpointErr != nil {
+					log.WithError(checkpointErr).Warnf("Failed to write checkpoint to %s", crawler.checkpointPath)
+				}
+			}
+			return
+		}
+
+		crawler.notifySearchEvent(LevelFinished, clock, nil)
+		state.advance(crawler.newFrontier())
+	}
+}
+
+// forgetPagesFrom drops all pages discovered after the first count pages
+func (crawler *WikiCrawler) forgetPagesFrom(count int) {
+	crawler.titles.Truncate(count)
+	crawler.parents.Truncate(count)
+	for link := range crawler.linkContexts {
+		if int(link) >= count {
+			delete(crawler.linkContexts, link)
+		}
+	}
+	for link := range crawler.interlanguageEdges {
+		if int(link) >= count {
+			delete(crawler.interlanguageEdges, link)
+		}
+	}
+}
+
+// initSearchState either restores the state of a previously checkpointed search or starts at the start page
+func (crawler *WikiCrawler) initSearchState() (state *searchState, err error) {
+	if crawler.resumeState != nil {
+		state = crawler.resumeState
+		crawler.resumeState = nil
+		return
+	}
+
+	state = &searchState{
+		current: crawler.newFrontier(),
+		next:    crawler.newFrontier(),
+	}
+
+	startID, _ := crawler.titles.Intern(crawler.startTitle)
+	if err = state.current.Push(startID); err != nil {
+		state.Close()
+		state = nil
+	}
+	return
+}
+
+func (crawler *WikiCrawler) updateProgress(state *searchState) {
+	crawler.progress.set(SearchProgress{
+		Depth:            state.depth,
+		FrontierSize:     state.current.Len(),
+		ProcessedPages:   state.processed,
+		NextFrontierSize: state.next.Len(),
+		FetchedPages:     crawler.fetchedPages,
+		CachedPages:      crawler.cachedPages,
+		DiscoveredPages:  crawler.titles.Len(),
+	})
+}
+
+func (crawler *WikiCrawler) processState(ctx context.Context, id pageID) (traversalResult TraversalResult, discoveredLinks []pageID) {
+	pageURI := crawler.pageURI(id)
+
+	ctx, span := crawler.tracer.Start(ctx, "processState", attribute("page.uri", pageURI))
+	defer span.End(nil)
+
+	var ok bool
+	if crawler.linkCache != nil {
+		discoveredLinks, ok = crawler.processCachedLinks(ctx, span, id, pageURI)
+	} else {
+		discoveredLinks, ok = crawler.processPageLinks(ctx, id, pageURI)
+	}
+	if !ok {
+		return
+	}
+	span.SetAttributes(attribute("page.discovered_links", len(discoveredLinks)))
+	crawler.notifyLinksDiscovered(pageURI, discoveredLinks)
+
+	for _, link := range discoveredLinks {
+		if crawler.titles.Title(link) == crawler.targetTitle {
+			return crawler.pathResult(link), discoveredLinks
+		}
+	}
+
+	return
+}
+
+// pathResult is the result of a search which found the path to the page
+func (crawler *WikiCrawler) pathResult(id pageID) TraversalResult {
+	return TraversalResult{
+		path:               crawler.parents.PathTo(id),
+		titles:             crawler.titles,
+		pageURI:            crawler.pageURI,
+		linkContexts:       crawler.linkContexts,
+		interlanguageEdges: crawler.interlanguageEdges,
+	}
+}
+
+// processPageLinks retrieves the page and interns its links directly while it is parsed.
+// ok is false if the page could not be retrieved or parsed.
+func (crawler *WikiCrawler) processPageLinks(ctx context.Context, id pageID, pageURI string) (discoveredLinks []pageID, ok bool) {
+	started := time.Now()
+	body := crawler.fetchBody(ctx, pageURI)
+	if body == nil {
+		return
+	}
+	defer body.Close()
+
+	log.WithField("pageURI", pageURI).Debug("Parsing retrieved HTML page")
+
+	pageWiki, _ := splitQualifiedTitle(crawler.titles.Title(id))
+	scope := linkScope{
+		site:          crawler.site,
+		wiki:          pageWiki,
+		startWiki:     crawler.wikiBaseDomain,
+		interlanguage: crawler.interlanguageEdges != nil,
+	}
+
+	// the body is streamed while it is parsed, the parse span covers reading the body as well
+	_, parseSpan := crawler.tracer.Start(ctx, "parse")
+	links, err := extractLinksFromContent(body, scope, crawler.titles, crawler.linkContexts)
+	parseSpan.SetAttributes(attribute("page.discovered_links", len(links)))
+	parseSpan.End(err)
+
+	if err != nil {
+		crawler.notifyParseError(pageURI, err)
+		return
+	}
+	crawler.notifyPageFetched(pageURI, time.Since(started))
+
+	for _, link := range links {
+		crawler.parents.Set(link.id, id)
+		if link.kind == InterlanguageLink {
+			crawler.interlanguageEdges[link.id] = true
+		}
+		discoveredLinks = append(discoveredLinks, link.id)
+	}
+	return discoveredLinks, true
+}
+
+// processCachedLinks takes the links of the page from the link cache or retrieves and parses the page to share its links via the cache.
+// Pages answered by the cache are counted as cached pages instead of fetched pages.
+// ok is false if the page could not be retrieved or parsed.
+func (crawler *WikiCrawler) processCachedLinks(ctx context.Context, span Span, id pageID, pageURI string) (discoveredLinks []pageID, ok bool) {
+	pageWiki, _ := splitQualifiedTitle(crawler.titles.Title(id))
+	if pageWiki == "" {
+		pageWiki = crawler.wikiBaseDomain
+	}
+	interlanguage := crawler.interlanguageEdges != nil
+	contexts := crawler.linkContexts != nil
+
+	links, cached := crawler.linkCache.get(pageURI, interlanguage, contexts)
+	span.SetAttributes(attribute("page.cached_links", cached))
+
+	if cached {
+		crawler.cachedPages += 1
+		crawler.notifyLinksCached(pageURI)
+	} else if links = crawler.fetchLinks(ctx, pageURI, pageWiki, interlanguage, contexts); links == nil {
+		return
+	}
+
+	for _, link := range links.links {
+		// cached links might have been collected for a search following interlanguage links
+		if link.kind == InterlanguageLink && !interlanguage {
+			continue
+		}
+		wiki, title := splitQualifiedTitle(link.key)
+		linkID, alreadyPresent := crawler.titles.Intern(qualifiedTitle(crawler.wikiBaseDomain, wiki, title))
+		if alreadyPresent {
+			continue
+		}
+		crawler.parents.Set(linkID, id)
+		if link.kind == InterlanguageLink {
+			crawler.interlanguageEdges[linkID] = true
+		}
+		if contexts && link.context != nil {
+			crawler.linkContexts[linkID] = *link.context
+		}
+		discoveredLinks = append(discoveredLinks, linkID)
+	}
+	return discoveredLinks, true
+}
+
+// fetchLinks retrieves and parses the page and shares its links via the link cache.
+// Failures are logged and notified to the observers, the links are nil then.
+func (crawler *WikiCrawler) fetchLinks(ctx context.Context, pageURI, pageWiki string, interlanguage, contexts bool) (links *pageLinks) {
+	started := time.Now()
+	body := crawler.fetchBody(ctx, pageURI)
+	if body == nil {
+		return
+	}
+	defer body.Close()
+
+	log.WithField("pageURI", pageURI).Debug("Parsing retrieved HTML page")
+
+	// the body is streamed while it is parsed, the parse span covers reading the body as well
+	_, parseSpan := crawler.tracer.Start(ctx, "parse")
+	links, err := parsePageLinks(body, crawler.site, pageWiki, interlanguage, contexts)
+	if err == nil {
+		parseSpan.SetAttributes(attribute("page.links", len(links.links)))
+	}
+	parseSpan.End(err)
+
+	if err != nil {
+		crawler.notifyParseError(pageURI, err)
+		return nil
+	}
+	crawler.notifyPageFetched(pageURI, time.Since(started))
+
+	crawler.linkCache.put(pageURI, links)
+	return
+}
+
+// fetchBody retrieves the page and counts it as fetched page.
+// Failures are logged and notified to the observers, the body is nil then.
+func (crawler *WikiCrawler) fetchBody(ctx context.Context, pageURI string) (body io.ReadCloser) {
+	logger := log.WithFields(log.Fields{
+		"pageURI": pageURI,
+	})
+
+	logger.Debug("Fetching wiki page")
+
+	fetchCtx, fetchSpan := crawler.tracer.Start(ctx, "fetch")
+	body, err := crawler.fetchPage(fetchCtx, pageURI)
+	fetchSpan.End(err)
+
+	crawler.fetchedPages += 1
+
+	if err != nil {
+		logger.Error("failed to retrieve page URI")
+		// retrievals aborted by a cancelled search are no failures of the page
+		if ctx.Err() == nil {
+			crawler.notifyFetchError(pageURI, err)
+		}
+		return nil
+	}
+	return
+}
+
+// notifyParseError logs that the retrieved page could not be parsed and notifies the observers
+func (crawler *WikiCrawler) notifyParseError(pageURI string, err error) {
+	log.WithError(err).Errorf("Failed to process page %s", pageURI)
+	crawler.notifyFetchError(pageURI, &ParseError{PageURI: pageURI, Err: err})
+}
+
+func (crawler WikiCrawler) newFrontier() frontier {
+	return newSpillingFrontier(crawler.spillDirectory, crawler.frontierMemoryLimit)
+}
+
+// pageURI materialises the full URI of an interned page, URIs are only required for fetching and output
+func (crawler WikiCrawler) pageURI(id pageID) string {
+	return crawler.titleURI(crawler.titles.Title(id))
+}
+
+// titleURI materialises the URI of an interned title, titles of other wikis carry their wiki
+func (crawler WikiCrawler) titleURI(key string) string {
+	wiki, title := splitQualifiedTitle(key)
+	if wiki == "" {
+		wiki = crawler.wikiBaseDomain
+	}
+	return crawler.site.articleURI(wiki, title)
+}
+
+// FetchPage retrieves the raw HTML of the page with the given URI.
+// All failures are reported as FetchError, responses with a status other than 2xx are errors as well.
+func FetchPage(ctx context.Context, pageURI string) (body io.ReadCloser, err error) {
+	return fetchPageWith(ctx, http.DefaultClient, pageURI)
+}
+
+// NewPageFetcher creates a fetcher behaving like FetchPage but retrieving all pages with the given client
+// e.g. to instrument its transport.
+func NewPageFetcher(client *http.Client) func(ctx context.Context, pageURI string) (io.ReadCloser, error) {
+	return func(ctx context.Context, pageURI string) (io.ReadCloser, error) {
+		return fetchPageWith(ctx, client, pageURI)
+	}
+}
+
+func fetchPageWith(ctx context.Context, client *http.Client, pageURI string) (body io.ReadCloser, err error) {
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, pageURI, nil); err != nil {
+		return
+	}
+
+	var resp *http.Response
+	if resp, err = client.Do(req); err != nil {
+		// the URL is part of the FetchError already
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		err = &FetchError{PageURI: pageURI, Err: err}
+		return
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		err = &FetchError{PageURI: pageURI, StatusCode: resp.StatusCode}
+		return
+	}
+	body = resp.Body
+	return
+}
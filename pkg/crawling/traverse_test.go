@@ -0,0 +1,740 @@
+// Copyright © 2019 Peter Kurfer peter.kurfer@googlemail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crawling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/baez90/shortest-path/pkg/crawling/crawlingtest"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_processState(t *testing.T) {
+	type args struct {
+		crawler *WikiCrawler
+		title   string
+	}
+	tests := []struct {
+		name             string
+		args             args
+		wantResultsCount int
+	}{
+		{
+			name: "Test to process Times 'New Roman article'",
+			args: args{
+				crawler: newFixtureCrawler("Times_New_Roman", "Great_Britain"),
+				title:   "Times_New_Roman",
+			},
+			wantResultsCount: 344,
+		},
+		{
+			name: "Test to process Manduca Jordani article",
+			args: args{
+				crawler: newFixtureCrawler("Manduca_jordani", "Great_Britain"),
+				title:   "Manduca_jordani",
+			},
+			wantResultsCount: 18,
+		},
+		{
+			name: "Test to process not existing article",
+			args: args{
+				crawler: newFixtureCrawler("Times_New_Roman", "Great_Britain"),
+				title:   "Helvetica",
+			},
+			wantResultsCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, _ := tt.args.crawler.titles.Intern(tt.args.title)
+			_, discoveredLinks := tt.args.crawler.processState(context.Background(), id)
+
+			if len(discoveredLinks) != tt.wantResultsCount {
+				t.Errorf("expected %d results but got %d", tt.wantResultsCount, len(discoveredLinks))
+			}
+
+			for _, link := range discoveredLinks {
+				if parent, ok := tt.args.crawler.parents.Parent(link); !ok || parent != id {
+					t.Errorf("expected parent of %d to be %d but got %d", link, id, parent)
+				}
+			}
+		})
+	}
+}
+
+func TestWikiCrawler_SearchShortestPath(t *testing.T) {
+	type args struct {
+		graph       crawlingtest.Tree
+		target      int
+		maxHops     uint16
+		memoryLimit uint64
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantPath  []string
+		wantErr   error
+		wantFetch uint
+	}{
+		{
+			name: "Find direct link",
+			args: args{
+				graph:   crawlingtest.Tree{Branching: 3, Nodes: 40},
+				target:  2,
+				maxHops: 5,
+			},
+			wantPath: []string{
+				"https://en.wikipedia.org/wiki/Node_c",
+				"https://en.wikipedia.org/wiki/Node_a",
+			},
+			wantFetch: 1,
+		},
+		{
+			name: "Find path across multiple levels",
+			args: args{
+				graph:   crawlingtest.Tree{Branching: 3, Nodes: 40},
+				target:  14,
+				maxHops: 5,
+			},
+			wantPath: []string{
+				"https://en.wikipedia.org/wiki/Node_o",
+				"https://en.wikipedia.org/wiki/Node_e",
+				"https://en.wikipedia.org/wiki/Node_b",
+				"https://en.wikipedia.org/wiki/Node_a",
+			},
+			wantFetch: 5,
+		},
+		{
+			name: "Find path with spilled frontier",
+			args: args{
+				graph:       crawlingtest.Tree{Branching: 3, Nodes: 400},
+				target:      364,
+				maxHops:     6,
+				memoryLimit: 8 * pageIDSize,
+			},
+			wantPath: []string{
+				"https://en.wikipedia.org/wiki/Node_oa",
+				"https://en.wikipedia.org/wiki/Node_er",
+				"https://en.wikipedia.org/wiki/Node_bo",
+				"https://en.wikipedia.org/wiki/Node_n",
+				"https://en.wikipedia.org/wiki/Node_e",
+				"https://en.wikipedia.org/wiki/Node_b",
+				"https://en.wikipedia.org/wiki/Node_a",
+			},
+			wantFetch: 122,
+		},
+		{
+			name: "Find start page as target without any hop",
+			args: args{
+				graph:   crawlingtest.Tree{Branching: 3, Nodes: 40},
+				target:  0,
+				maxHops: 2,
+			},
+			wantPath: []string{
+				"https://en.wikipedia.org/wiki/Node_a",
+			},
+		},
+		{
+			name: "Fail if target is too far away",
+			args: args{
+				graph:   crawlingtest.Tree{Branching: 3, Nodes: 40},
+				target:  39,
+				maxHops: 2,
+			},
+			wantErr:   ErrMaxHopsReached,
+			wantFetch: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crawler := treeCrawler(tt.args.graph, tt.args.target, tt.args.maxHops, WithFrontierSpilling("", tt.args.memoryLimit))
+			result, err := crawler.SearchShortestPath(context.Background())
+			if err != tt.wantErr {
+				t.Errorf("SearchShortestPath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotPath := result.VisitedPages(); !reflect.DeepEqual(gotPath, tt.wantPath) {
+				t.Errorf("SearchShortestPath() path = %v, want %v", gotPath, tt.wantPath)
+			}
+			if crawler.FetchedPages() != tt.wantFetch {
+				t.Errorf("Expected %d fetched pages but got %d", tt.wantFetch, crawler.FetchedPages())
+			}
+		})
+	}
+}
+
+// eventObserver records the search events as kind@depth
+type eventObserver struct {
+	NopObserver
+	events []string
+	path   []PathPage
+}
+
+func (observer *eventObserver) record(event SearchEvent) {
+	observer.events = append(observer.events, fmt.Sprintf("%s@%d", event.Kind, event.Progress.Depth))
+}
+
+func (observer *eventObserver) OnLevelStart(event SearchEvent)    { observer.record(event) }
+func (observer *eventObserver) OnLevelFinished(event SearchEvent) { observer.record(event) }
+func (observer *eventObserver) OnPageProcessed(event SearchEvent) { observer.record(event) }
+
+func (observer *eventObserver) OnPathFound(event SearchEvent) {
+	observer.record(event)
+	observer.path = event.Path
+}
+
+func TestWikiCrawler_SearchShortestPath_Events(t *testing.T) {
+	graph := crawlingtest.Tree{Branching: 3, Nodes: 40}
+	observer := &eventObserver{}
+	crawler := treeCrawler(graph, 14, 5, WithObserver(observer))
+
+	if _, err := crawler.SearchShortestPath(context.Background()); err != nil {
+		t.Fatalf("SearchShortestPath() error = %v", err)
+	}
+
+	wantEvents := []string{
+		"level_started@0", "page_processed@0", "level_finished@0",
+		"level_started@1", "page_processed@1", "page_processed@1", "page_processed@1", "level_finished@1",
+		"level_started@2", "path_found@2",
+	}
+	if !reflect.DeepEqual(observer.events, wantEvents) {
+		t.Errorf("events = %v, want %v", observer.events, wantEvents)
+	}
+	if len(observer.path) != 4 || observer.path[3].URI != "https://en.wikipedia.org/wiki/Node_o" {
+		t.Errorf("path of path_found event = %v", observer.path)
+	}
+}
+
+// recordingObserver records the steps of a search in a compact notation
+type recordingObserver struct {
+	NopObserver
+	calls []string
+}
+
+func (observer *recordingObserver) OnLevelStart(event SearchEvent) {
+	observer.calls = append(observer.calls, fmt.Sprintf("level %d with %d pages", event.Progress.Depth, event.Progress.FrontierSize))
+}
+
+func (observer *recordingObserver) OnPageFetched(pageURI string, _ time.Duration) {
+	observer.calls = append(observer.calls, "fetched "+path.Base(pageURI))
+}
+
+func (observer *recordingObserver) OnLinksDiscovered(pageURI string, links []string) {
+	observer.calls = append(observer.calls, fmt.Sprintf("discovered %d links on %s", len(links), path.Base(pageURI)))
+}
+
+func (observer *recordingObserver) OnFetchError(pageURI string, _ error) {
+	observer.calls = append(observer.calls, "failed "+path.Base(pageURI))
+}
+
+func (observer *recordingObserver) OnPathFound(event SearchEvent) {
+	observer.calls = append(observer.calls, fmt.Sprintf("found path of %d pages", len(event.Path)))
+}
+
+func (observer *recordingObserver) OnSearchFinished(hops int, _ time.Duration, err error) {
+	observer.calls = append(observer.calls, fmt.Sprintf("finished with %d hops and error %v", hops, err))
+}
+
+func TestWikiCrawler_WithObserver(t *testing.T) {
+	graph := crawlingtest.Tree{Branching: 3, Nodes: 40}
+	observers := []*recordingObserver{{}, {}}
+	crawler := treeCrawler(graph, 14, 5, WithObserver(observers[0]), WithObserver(observers[1]))
+	fetch := crawler.fetchPage
+	crawler.fetchPage = func(ctx context.Context, pageURI string) (io.ReadCloser, error) {
+		if strings.HasSuffix(pageURI, "/Node_c") {
+			return nil, errors.New("503 Service Unavailable")
+		}
+		return fetch(ctx, pageURI)
+	}
+
+	if _, err := crawler.SearchShortestPath(context.Background()); err != nil {
+		t.Fatalf("SearchShortestPath() error = %v", err)
+	}
+
+	wantCalls := []string{
+		"level 0 with 1 pages", "fetched Node_a", "discovered 3 links on Node_a",
+		"level 1 with 3 pages", "fetched Node_b", "discovered 3 links on Node_b",
+		"failed Node_c",
+		"fetched Node_d", "discovered 3 links on Node_d",
+		"level 2 with 6 pages", "fetched Node_e", "discovered 3 links on Node_e", "found path of 4 pages",
+		"finished with 3 hops and error <nil>",
+	}
+	for idx, observer := range observers {
+		if !reflect.DeepEqual(observer.calls, wantCalls) {
+			t.Errorf("calls of observer %d = %v, want %v", idx, observer.calls, wantCalls)
+		}
+	}
+}
+
+// spanRecorder keeps all ended spans in memory, it records the name of the parent span in the context of every span
+type spanRecorder struct {
+	spans []*recordedSpan
+}
+
+type recordedSpan struct {
+	recorder *spanRecorder
+	name     string
+	parent   string
+	err      error
+}
+
+type spanNameKey struct{}
+
+func (recorder *spanRecorder) Start(ctx context.Context, name string, _ ...SpanAttribute) (context.Context, Span) {
+	parent, _ := ctx.Value(spanNameKey{}).(string)
+	return context.WithValue(ctx, spanNameKey{}, name), &recordedSpan{recorder: recorder, name: name, parent: parent}
+}
+
+func (span *recordedSpan) SetAttributes(...SpanAttribute) {}
+
+func (span *recordedSpan) End(err error) {
+	span.err = err
+	span.recorder.spans = append(span.recorder.spans, span)
+}
+
+func TestWikiCrawler_WithTracer(t *testing.T) {
+	recorder := &spanRecorder{}
+	graph := crawlingtest.Tree{Branching: 3, Nodes: 40}
+	crawler := treeCrawler(graph, 14, 5, WithTracer(recorder))
+	if _, err := crawler.SearchShortestPath(context.Background()); err != nil {
+		t.Fatalf("SearchShortestPath() error = %v", err)
+	}
+
+	wantParents := map[string]string{
+		"SearchShortestPath": "",
+		"level":              "SearchShortestPath",
+		"processState":       "level",
+		"fetch":              "processState",
+		"parse":              "processState",
+	}
+	counts := make(map[string]int)
+	for _, span := range recorder.spans {
+		counts[span.name]++
+		if span.parent != wantParents[span.name] {
+			t.Errorf("parent of span %s = %q, want %q", span.name, span.parent, wantParents[span.name])
+		}
+		// finding the target ends the level early but fails no span
+		if span.err != nil {
+			t.Errorf("span %s ended with error %v", span.name, span.err)
+		}
+	}
+
+	// levels 0 and 1 are processed completely, on level 2 the target is discovered on the first page
+	wantCounts := map[string]int{"SearchShortestPath": 1, "level": 3, "processState": 5, "fetch": 5, "parse": 5}
+	if !reflect.DeepEqual(counts, wantCounts) {
+		t.Errorf("span counts = %v, want %v", counts, wantCounts)
+	}
+}
+
+func TestWikiCrawler_SearchShortestPath_LinkContext(t *testing.T) {
+	graph := crawlingtest.Tree{Branching: 3, Nodes: 40}
+	crawler := treeCrawler(graph, 14, 5, WithLinkContext())
+
+	result, err := crawler.SearchShortestPath(context.Background())
+	if err != nil {
+		t.Fatalf("SearchShortestPath() error = %v", err)
+	}
+
+	path := result.Path()
+	if path[0].Link != nil {
+		t.Errorf("Expected start page to have no link context but got %+v", path[0].Link)
+	}
+
+	for _, page := range path[1:] {
+		wantAnchorText := strings.TrimPrefix(page.URI, "https://en.wikipedia.org/wiki/")
+		if page.Link == nil {
+			t.Errorf("Expected link context for page %s", page.URI)
+			continue
+		}
+		if page.Link.AnchorText != wantAnchorText {
+			t.Errorf("Anchor text of link to %s = %s, want %s", page.URI, page.Link.AnchorText, wantAnchorText)
+		}
+		if !strings.Contains(page.Link.Snippet, wantAnchorText) {
+			t.Errorf("Expected snippet %q to contain anchor text %q", page.Link.Snippet, wantAnchorText)
+		}
+	}
+}
+
+func TestWikiCrawler_SearchShortestPath_Interlanguage(t *testing.T) {
+	pages := map[string]string{
+		"https://de.wikipedia.org/wiki/Schriftart": `<div id="bodyContent"><p><a href="/wiki/Serife">Serife</a></p></div>
+<div id="p-lang"><ul><li class="interlanguage-link interwiki-en"><a href="https://en.wikipedia.org/wiki/Typeface">English</a></li></ul></div>`,
+		"https://de.wikipedia.org/wiki/Serife": `<div id="bodyContent"><p>Serifen</p></div>`,
+		"https://en.wikipedia.org/wiki/Typeface": `<div id="bodyContent"><p><a href="/wiki/Times_New_Roman">Times New Roman</a></p></div>
+<div id="p-lang"><ul><li class="interlanguage-link interwiki-de"><a href="//de.wikipedia.org/wiki/Schriftart">Deutsch</a></li></ul></div>`,
+	}
+
+	wantPath := []PathPage{
+		{Title: "Schriftart", URI: "https://de.wikipedia.org/wiki/Schriftart"},
+		{Title: "en:Typeface", URI: "https://en.wikipedia.org/wiki/Typeface", Interlanguage: true},
+		{Title: "en:Times New Roman", URI: "https://en.wikipedia.org/wiki/Times_New_Roman"},
+	}
+
+	tests := []struct {
+		name          string
+		target        string
+		interlanguage bool
+		wantPath      []PathPage
+		wantErr       bool
+	}{
+		{
+			name:          "hop to English target via interlanguage link",
+			target:        "https://en.wikipedia.org/wiki/Times_New_Roman",
+			interlanguage: true,
+			wantPath:      wantPath,
+		},
+		{
+			name:          "match target given with http scheme",
+			target:        "http://en.wikipedia.org/wiki/Times_New_Roman",
+			interlanguage: true,
+			wantPath:      wantPath,
+		},
+		{
+			name:          "match target given on mobile site",
+			target:        "https://en.m.wikipedia.org/wiki/Times_New_Roman",
+			interlanguage: true,
+			wantPath:      wantPath,
+		},
+		{
+			name:    "target in other language edition is unreachable without interlanguage links",
+			target:  "https://en.wikipedia.org/wiki/Times_New_Roman",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := []Option{WithMaxHops(3)}
+			if tt.interlanguage {
+				options = append(options, WithInterlanguageLinks())
+			}
+			crawler := MustNewWikiCrawler("https://de.wikipedia.org/wiki/Schriftart", tt.target, options...)
+			crawler.fetchPage = func(ctx context.Context, pageURI string) (io.ReadCloser, error) {
+				page, ok := pages[pageURI]
+				if !ok {
+					return nil, fmt.Errorf("no page %s", pageURI)
+				}
+				return ioutil.NopCloser(strings.NewReader(page)), nil
+			}
+			result, err := crawler.SearchShortestPath(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SearchShortestPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotPath := result.Path(); !reflect.DeepEqual(gotPath, tt.wantPath) {
+				t.Errorf("SearchShortestPath() path = %+v, want %+v", gotPath, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestWikiCrawler_SearchShortestPath_Cancel(t *testing.T) {
+	type args struct {
+		graph       crawlingtest.Tree
+		cancelAfter uint
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantProgress SearchProgress
+	}{
+		{
+			name: "Cancel while processing the start page",
+			args: args{
+				graph:       crawlingtest.Tree{Branching: 3, Nodes: 400},
+				cancelAfter: 1,
+			},
+			wantProgress: SearchProgress{
+				Depth:           0,
+				FrontierSize:    1,
+				ProcessedPages:  0,
+				FetchedPages:    1,
+				DiscoveredPages: 1,
+			},
+		},
+		{
+			name: "Cancel within the fourth level",
+			args: args{
+				graph:       crawlingtest.Tree{Branching: 3, Nodes: 400},
+				cancelAfter: 20,
+			},
+			wantProgress: SearchProgress{
+				Depth:            3,
+				FrontierSize:     27,
+				ProcessedPages:   6,
+				NextFrontierSize: 18,
+				FetchedPages:     20,
+				DiscoveredPages:  58,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			crawler := treeCrawler(tt.args.graph, tt.args.graph.Nodes, 10)
+			fetchPage := crawler.fetchPage
+			crawler.fetchPage = func(ctx context.Context, pageURI string) (io.ReadCloser, error) {
+				if crawler.fetchedPages+1 == tt.args.cancelAfter {
+					cancel()
+				}
+				return fetchPage(ctx, pageURI)
+			}
+
+			if _, err := crawler.SearchShortestPath(ctx); err != context.Canceled {
+				t.Errorf("SearchShortestPath() error = %v, want %v", err, context.Canceled)
+			}
+
+			if gotProgress := crawler.Progress(); !reflect.DeepEqual(gotProgress, tt.wantProgress) {
+				t.Errorf("Progress() = %+v, want %+v", gotProgress, tt.wantProgress)
+			}
+
+			if crawler.Progress().RuledOutHops() != tt.wantProgress.Depth {
+				t.Errorf("RuledOutHops() = %d, want %d", crawler.Progress().RuledOutHops(), tt.wantProgress.Depth)
+			}
+		})
+	}
+}
+
+// BenchmarkWikiCrawler_SearchShortestPath runs an exhaustive search on a synthetic graph.
+// Besides the allocations the live heap after the search is reported to show how much of the explored graph stays reachable.
+func BenchmarkWikiCrawler_SearchShortestPath(b *testing.B) {
+	graph := crawlingtest.Tree{Branching: 20, Nodes: 50000}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		crawler := treeCrawler(graph, graph.Nodes, 10)
+		if _, err := crawler.SearchShortestPath(context.Background()); err == nil {
+			b.Fatal("did not expect to find a path")
+		}
+		b.ReportMetric(float64(liveHeap()), "live-heap-B")
+		runtime.KeepAlive(crawler)
+	}
+}
+
+// legacyTraversalState mirrors the former search tree where every node referenced all of its children
+type legacyTraversalState struct {
+	PageURI     string
+	Predecessor *legacyTraversalState
+	Ancestors   []*legacyTraversalState
+}
+
+// BenchmarkSearchTree_LegacyStates builds the bookkeeping the former search tree required for the synthetic graph
+func BenchmarkSearchTree_LegacyStates(b *testing.B) {
+	graph := crawlingtest.Tree{Branching: 20, Nodes: 50000}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		visitedPages := map[string]bool{graph.PageURI(0): true}
+		root := &legacyTraversalState{PageURI: graph.PageURI(0)}
+		frontier := []*legacyTraversalState{root}
+		for len(frontier) > 0 {
+			next := make([]*legacyTraversalState, 0)
+			for _, state := range frontier {
+				for _, child := range graph.Children(graph.Node(state.PageURI)) {
+					if visitedPages[graph.PageURI(child)] {
+						continue
+					}
+					visitedPages[graph.PageURI(child)] = true
+					ancestor := &legacyTraversalState{PageURI: graph.PageURI(child), Predecessor: state}
+					state.Ancestors = append(state.Ancestors, ancestor)
+				}
+				next = append(next, state.Ancestors...)
+			}
+			frontier = next
+		}
+		b.ReportMetric(float64(liveHeap()), "live-heap-B")
+		runtime.KeepAlive(root)
+		runtime.KeepAlive(visitedPages)
+	}
+}
+
+// BenchmarkSearchTree_ParentMap builds the bookkeeping of the current search for the synthetic graph
+func BenchmarkSearchTree_ParentMap(b *testing.B) {
+	graph := crawlingtest.Tree{Branching: 20, Nodes: 50000}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		titles := newTitleInterner()
+		parents := &parentMap{}
+		root, _ := titles.Intern(graph.Title(0))
+		frontier := []pageID{root}
+		for len(frontier) > 0 {
+			next := make([]pageID, 0)
+			for _, id := range frontier {
+				for _, child := range graph.Children(graph.Node(titles.Title(id))) {
+					if childID, alreadyPresent := titles.Intern(graph.Title(child)); !alreadyPresent {
+						parents.Set(childID, id)
+						next = append(next, childID)
+					}
+				}
+			}
+			frontier = next
+		}
+		b.ReportMetric(float64(liveHeap()), "live-heap-B")
+		runtime.KeepAlive(titles)
+		runtime.KeepAlive(parents)
+	}
+}
+
+func TestNewWikiCrawler(t *testing.T) {
+	tests := []struct {
+		name        string
+		startPage   string
+		options     []Option
+		wantMaxHops uint16
+		wantErr     error
+	}{
+		{
+			name:        "default max hops",
+			startPage:   "https://en.wikipedia.org/wiki/Times_New_Roman",
+			wantMaxHops: DefaultMaxHops,
+		},
+		{
+			name:        "apply options",
+			startPage:   "https://en.wikipedia.org/wiki/Times_New_Roman",
+			options:     []Option{WithMaxHops(3), WithLinkContext()},
+			wantMaxHops: 3,
+		},
+		{
+			name:      "reject title",
+			startPage: "Times New Roman",
+			wantErr:   ErrInvalidArticleURL,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crawler, err := NewWikiCrawler(tt.startPage, "https://en.wikipedia.org/wiki/Helvetica", tt.options...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewWikiCrawler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if crawler.maxHops != tt.wantMaxHops {
+				t.Errorf("NewWikiCrawler() max hops = %d, want %d", crawler.maxHops, tt.wantMaxHops)
+			}
+			if wantLinkContext := len(tt.options) > 1; (crawler.linkContexts != nil) != wantLinkContext {
+				t.Errorf("NewWikiCrawler() link context enabled = %v, want %v", crawler.linkContexts != nil, wantLinkContext)
+			}
+		})
+	}
+}
+
+func TestFetchPage_errors(t *testing.T) {
+	wiki := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wiki/Missing":
+			http.NotFound(w, r)
+		default:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}
+	}))
+	defer wiki.Close()
+
+	tests := []struct {
+		name           string
+		pageURI        string
+		wantStatusCode int
+		wantNotFound   bool
+	}{
+		{
+			name:           "missing page",
+			pageURI:        wiki.URL + "/wiki/Missing",
+			wantStatusCode: http.StatusNotFound,
+			wantNotFound:   true,
+		},
+		{
+			name:           "unavailable wiki",
+			pageURI:        wiki.URL + "/wiki/Helvetica",
+			wantStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:    "unreachable wiki",
+			pageURI: "http://127.0.0.1:0/wiki/Helvetica",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FetchPage(context.Background(), tt.pageURI)
+			var fetchErr *FetchError
+			if !errors.As(err, &fetchErr) {
+				t.Fatalf("FetchPage() error = %v, want FetchError", err)
+			}
+			if fetchErr.PageURI != tt.pageURI {
+				t.Errorf("FetchError.PageURI = %s, want %s", fetchErr.PageURI, tt.pageURI)
+			}
+			if fetchErr.StatusCode != tt.wantStatusCode {
+				t.Errorf("FetchError.StatusCode = %d, want %d", fetchErr.StatusCode, tt.wantStatusCode)
+			}
+			if (fetchErr.StatusCode == 0) != (fetchErr.Err != nil) {
+				t.Errorf("FetchError.Err = %v, want a cause only without response", fetchErr.Err)
+			}
+			if errors.Is(err, ErrPageNotFound) != tt.wantNotFound {
+				t.Errorf("errors.Is(%v, ErrPageNotFound) = %v, want %v", err, !tt.wantNotFound, tt.wantNotFound)
+			}
+		})
+	}
+}
+
+func MustNewWikiCrawler(startPage, targetPage string, options ...Option) *WikiCrawler {
+	crawler, err := NewWikiCrawler(startPage, targetPage, options...)
+	if err != nil {
+		panic(err)
+	}
+	return crawler
+}
+
+func newFixtureCrawler(startTitle, targetTitle string) *WikiCrawler {
+	fixtures := map[string]string{
+		"Times_New_Roman": "../../assets/test-data/times_new_roman_article.html",
+		"Manduca_jordani": "../../assets/test-data/manduca_jordani_article.html",
+	}
+	crawler := MustNewWikiCrawler(
+		"https://en.wikipedia.org/wiki/"+startTitle,
+		"https://en.wikipedia.org/wiki/"+targetTitle,
+		WithMaxHops(10),
+	)
+	crawler.fetchPage = func(ctx context.Context, pageURI string) (io.ReadCloser, error) {
+		fixture, ok := fixtures[strings.TrimPrefix(pageURI, "https://en.wikipedia.org/wiki/")]
+		if !ok {
+			return nil, fmt.Errorf("no fixture for page %s", pageURI)
+		}
+		return os.Open(fixture)
+	}
+	return crawler
+}
+
+// treeCrawler creates a crawler searching the tree wiki from its root to target
+func treeCrawler(tree crawlingtest.Tree, target int, maxHops uint16, options ...Option) *WikiCrawler {
+	crawler := MustNewWikiCrawler(tree.PageURI(0), tree.PageURI(target), append([]Option{WithMaxHops(maxHops)}, options...)...)
+	crawler.fetchPage = tree.Fetch
+	return crawler
+}
+
+func liveHeap() uint64 {
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}